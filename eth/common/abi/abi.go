@@ -0,0 +1,191 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package abi implements the Solidity contract ABI: parsing JSON ABI
+// definitions, computing method selectors and event topics, encoding call
+// data and decoding return values and event logs.
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+// ABI holds the methods and events of a contract.
+type ABI struct {
+	Constructor Method
+	Methods     map[string]Method
+	Events      map[string]Event
+
+	// Fallback and Receive are the special functions of the contract; their
+	// Name is empty if the contract does not define them.
+	Fallback Method
+	Receive  Method
+}
+
+// JSON parses a JSON ABI definition.
+func JSON(reader io.Reader) (ABI, error) {
+	dec := json.NewDecoder(reader)
+	var abi ABI
+	if err := dec.Decode(&abi); err != nil {
+		return ABI{}, err
+	}
+	return abi, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (abi *ABI) UnmarshalJSON(data []byte) error {
+	var fields []struct {
+		Type            string
+		Name            string
+		Inputs          []Argument
+		Outputs         []Argument
+		StateMutability string
+		Anonymous       bool
+
+		// Deprecated fields of older compilers.
+		Constant bool
+		Payable  bool
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	abi.Methods = make(map[string]Method)
+	abi.Events = make(map[string]Event)
+	for _, field := range fields {
+		mutability := field.StateMutability
+		if mutability == "" {
+			switch {
+			case field.Constant:
+				mutability = "view"
+			case field.Payable:
+				mutability = "payable"
+			default:
+				mutability = "nonpayable"
+			}
+		}
+		switch field.Type {
+		case "constructor":
+			abi.Constructor = NewMethod("", "", mutability, field.Inputs, nil)
+		case "function", "":
+			name := overloadedName(field.Name, func(s string) bool { _, ok := abi.Methods[s]; return ok })
+			abi.Methods[name] = NewMethod(name, field.Name, mutability, field.Inputs, field.Outputs)
+		case "fallback":
+			abi.Fallback = NewMethod("fallback", "", mutability, nil, nil)
+		case "receive":
+			abi.Receive = NewMethod("receive", "", mutability, nil, nil)
+		case "event":
+			name := overloadedName(field.Name, func(s string) bool { _, ok := abi.Events[s]; return ok })
+			abi.Events[name] = NewEvent(name, field.Name, field.Anonymous, field.Inputs)
+		case "error":
+			// Custom errors are not supported yet.
+		default:
+			return fmt.Errorf("abi: could not recognize type %v of field %v", field.Type, field.Name)
+		}
+	}
+	return nil
+}
+
+// overloadedName returns rawName if it is not yet taken, or rawName with
+// the lowest free numeric suffix.
+func overloadedName(rawName string, taken func(string) bool) string {
+	name := rawName
+	for i := 0; taken(name); i++ {
+		name = fmt.Sprintf("%s%d", rawName, i)
+	}
+	return name
+}
+
+// Pack encodes a call of the named method, prefixed with its selector. An
+// empty name packs the constructor arguments, which are not prefixed.
+func (abi ABI) Pack(name string, args ...interface{}) ([]byte, error) {
+	if name == "" {
+		return abi.Constructor.Inputs.Pack(args...)
+	}
+	method, ok := abi.Methods[name]
+	if !ok {
+		return nil, fmt.Errorf("abi: method '%s' not found", name)
+	}
+	enc, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(method.ID[:], enc...), nil
+}
+
+// Unpack decodes the return values of the named method, or the data of the
+// named event.
+func (abi ABI) Unpack(name string, data []byte) ([]interface{}, error) {
+	if method, ok := abi.Methods[name]; ok {
+		return method.Outputs.Unpack(data)
+	}
+	if event, ok := abi.Events[name]; ok {
+		return event.Inputs.Unpack(data)
+	}
+	return nil, fmt.Errorf("abi: could not locate named method or event: %s", name)
+}
+
+// MethodById returns the method with the given selector.
+func (abi *ABI) MethodById(sel types.Selector) (*Method, error) {
+	for _, method := range abi.Methods {
+		if method.ID == sel {
+			return &method, nil
+		}
+	}
+	return nil, fmt.Errorf("abi: no method with id: %v", sel)
+}
+
+// MethodByData returns the method called by the given call data.
+func (abi *ABI) MethodByData(data []byte) (*Method, error) {
+	var sel types.Selector
+	if len(data) < len(sel) {
+		return nil, errors.New("abi: call data too short")
+	}
+	copy(sel[:], data)
+	return abi.MethodById(sel)
+}
+
+// EventByID returns the event with the given topic.
+func (abi *ABI) EventByID(topic common.Hash) (*Event, error) {
+	for _, event := range abi.Events {
+		if event.ID == topic {
+			return &event, nil
+		}
+	}
+	return nil, fmt.Errorf("abi: no event with id: %#x", topic)
+}
+
+// DecodeLog finds the event of a non-anonymous log by its first topic and
+// decodes its arguments.
+func (abi *ABI) DecodeLog(log *types.Log) (*Event, map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil, errors.New("abi: log without topics")
+	}
+	event, err := abi.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := event.DecodeLog(log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return event, args, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+const erc20ABI = `[
+	{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"approve","constant":false,"inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Memo","anonymous":true,"inputs":[{"name":"tag","type":"string","indexed":true},{"name":"text","type":"string"}]},
+	{"type":"receive","stateMutability":"payable"}
+]`
+
+func parseERC20(t *testing.T) ABI {
+	t.Helper()
+	abi, err := JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abi
+}
+
+func TestJSON(t *testing.T) {
+	abi := parseERC20(t)
+	for name, want := range map[string]string{
+		"balanceOf": "0x70a08231",
+		"transfer":  "0xa9059cbb",
+		"transfer0": "0xbe45fd62",
+		"approve":   "0x095ea7b3",
+	} {
+		method, ok := abi.Methods[name]
+		if !ok {
+			t.Fatalf("method %s missing", name)
+		}
+		if method.ID.String() != want {
+			t.Errorf("%s: selector %v, want %s", method.Sig, method.ID, want)
+		}
+	}
+	if got := abi.Methods["transfer0"].String(); got != "function transfer(address to, uint256 value, bytes data)" {
+		t.Errorf("wrong declaration %q", got)
+	}
+	if got := abi.Methods["balanceOf"].String(); got != "function balanceOf(address owner) view returns(uint256)" {
+		t.Errorf("wrong declaration %q", got)
+	}
+	if abi.Methods["approve"].StateMutability != "nonpayable" || abi.Receive.StateMutability != "payable" {
+		t.Error("wrong state mutability")
+	}
+	want := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	if abi.Events["Transfer"].ID != want {
+		t.Errorf("wrong Transfer topic %x", abi.Events["Transfer"].ID)
+	}
+
+	if _, err := JSON(strings.NewReader(`[{"type":"function","name":"f","inputs":[{"name":"x","type":"uint7"}]}]`)); err == nil {
+		t.Error("invalid type accepted")
+	}
+	if _, err := JSON(strings.NewReader(`[{"type":"modifier","name":"f"}]`)); err == nil {
+		t.Error("unknown field type accepted")
+	}
+}
+
+func TestPackCall(t *testing.T) {
+	abi := parseERC20(t)
+	to := common.HexToAddress("0x5df9b87991262f6ba471f09758cde1c0fc1de734")
+	data, err := abi.Pack("transfer", to, big.NewInt(31337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(common.FromHex("a9059cbb"), words("5df9b87991262f6ba471f09758cde1c0fc1de734", "7a69")...)
+	if !reflect.DeepEqual(data, want) {
+		t.Fatalf("got %x, want %x", data, want)
+	}
+
+	// The selector of a message calling the method finds it again.
+	msg := types.NewMessage(common.Address{}, &to, 0, big.NewInt(0), 0, big.NewInt(0), nil, nil, data, nil, false)
+	sel, _ := msg.Selector()
+	method, err := abi.MethodById(sel)
+	if err != nil || method.Name != "transfer" {
+		t.Fatalf("got method %v, err %v", method, err)
+	}
+	if method, err = abi.MethodByData(data); err != nil || method.Name != "transfer" {
+		t.Fatalf("got method %v, err %v", method, err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil || args[0] != to || args[1].(*big.Int).Int64() != 31337 {
+		t.Fatalf("unpacked %v, err %v", args, err)
+	}
+
+	if _, err := abi.Pack("transfer", to); err == nil {
+		t.Error("packed call with missing argument")
+	}
+	if _, err := abi.Pack("mint", to); err == nil {
+		t.Error("packed unknown method")
+	}
+	ctor, err := abi.Pack("", big.NewInt(1))
+	if err != nil || !reflect.DeepEqual(ctor, words("01")) {
+		t.Errorf("constructor arguments %x, err %v", ctor, err)
+	}
+
+	out, err := abi.Unpack("balanceOf", words("0de0b6b3a7640000"))
+	if err != nil || out[0].(*big.Int).String() != "1000000000000000000" {
+		t.Errorf("unpacked %v, err %v", out, err)
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	abi := parseERC20(t)
+	from, to := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	transfer := abi.Events["Transfer"]
+	data, err := transfer.Inputs.Pack(big.NewInt(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := &types.Log{
+		Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   data,
+	}
+	event, args, err := abi.DecodeLog(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Name != "Transfer" || args["from"] != from || args["to"] != to || args["value"].(*big.Int).Int64() != 500 {
+		t.Fatalf("decoded %s %v", event.Name, args)
+	}
+
+	// Logs of other events and with missing topics are rejected.
+	if _, err := transfer.DecodeLog(&types.Log{Topics: []common.Hash{{1}}, Data: data}); err == nil {
+		t.Error("decoded log of another event")
+	}
+	if _, err := transfer.DecodeLog(&types.Log{Topics: log.Topics[:2], Data: data}); err == nil {
+		t.Error("decoded log with missing topic")
+	}
+
+	// Indexed dynamic values decode as the hash of their value.
+	memo := abi.Events["Memo"]
+	data, _ = memo.Inputs.Pack("hello")
+	tag := crypto.Keccak256Hash([]byte("greeting"))
+	args, err = memo.DecodeLog(&types.Log{Topics: []common.Hash{tag}, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args["tag"] != tag || args["text"] != "hello" {
+		t.Fatalf("decoded %v", args)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Argument is a named and typed input or output of a method or event.
+type Argument struct {
+	Name    string
+	Type    Type
+	Indexed bool // indexed is only used by events
+}
+
+// Arguments is the list of inputs or outputs of a method or event.
+type Arguments []Argument
+
+// ArgumentMarshaling is the JSON ABI representation of an argument.
+type ArgumentMarshaling struct {
+	Name         string               `json:"name"`
+	Type         string               `json:"type"`
+	InternalType string               `json:"internalType,omitempty"`
+	Components   []ArgumentMarshaling `json:"components,omitempty"`
+	Indexed      bool                 `json:"indexed,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Argument) UnmarshalJSON(data []byte) error {
+	var arg ArgumentMarshaling
+	if err := json.Unmarshal(data, &arg); err != nil {
+		return fmt.Errorf("abi: failed to unmarshal argument: %v", err)
+	}
+	typ, err := NewType(arg.Type, arg.Components)
+	if err != nil {
+		return err
+	}
+	*a = Argument{Name: arg.Name, Type: typ, Indexed: arg.Indexed}
+	return nil
+}
+
+// NonIndexed returns the arguments which are not indexed, i.e. the ones
+// encoded in the data of an event log.
+func (arguments Arguments) NonIndexed() Arguments {
+	var ret Arguments
+	for _, arg := range arguments {
+		if !arg.Indexed {
+			ret = append(ret, arg)
+		}
+	}
+	return ret
+}
+
+func (arguments Arguments) types() []*Type {
+	types := make([]*Type, len(arguments))
+	for i := range arguments {
+		types[i] = &arguments[i].Type
+	}
+	return types
+}
+
+// Pack encodes args as the non-indexed arguments.
+func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
+	abiArgs := arguments.NonIndexed()
+	if len(args) != len(abiArgs) {
+		return nil, fmt.Errorf("abi: argument count mismatch: got %d for %d", len(args), len(abiArgs))
+	}
+	values := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		values[i] = reflect.ValueOf(arg)
+	}
+	return packTuple(abiArgs.types(), values)
+}
+
+// Unpack decodes the non-indexed arguments from data. The values have the
+// Go types given by Type.GetType.
+func (arguments Arguments) Unpack(data []byte) ([]interface{}, error) {
+	abiArgs := arguments.NonIndexed()
+	if len(data) == 0 && len(abiArgs) > 0 {
+		return nil, errShortData
+	}
+	values := make([]reflect.Value, len(abiArgs))
+	if err := decodeSeq(abiArgs.types(), data, func(i int) reflect.Value {
+		values[i] = reflect.New(abiArgs[i].Type.goType).Elem()
+		return values[i]
+	}); err != nil {
+		return nil, err
+	}
+	ret := make([]interface{}, len(values))
+	for i, v := range values {
+		ret[i] = v.Interface()
+	}
+	return ret, nil
+}
+
+// UnpackIntoMap decodes the non-indexed arguments from data into v, keyed
+// by argument name.
+func (arguments Arguments) UnpackIntoMap(v map[string]interface{}, data []byte) error {
+	values, err := arguments.Unpack(data)
+	if err != nil {
+		return err
+	}
+	for i, arg := range arguments.NonIndexed() {
+		v[arg.Name] = values[i]
+	}
+	return nil
+}
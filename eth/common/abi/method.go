@@ -0,0 +1,172 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+// Method is a contract function, constructor, fallback or receive function.
+type Method struct {
+	// Name is the key of the method in ABI.Methods. Overloaded functions
+	// get a numeric suffix, starting at 0 for the second one.
+	Name string
+	// RawName is the name of the function in the contract.
+	RawName string
+
+	StateMutability string
+	Inputs          Arguments
+	Outputs         Arguments
+
+	// Sig is the canonical signature, e.g. "transfer(address,uint256)".
+	Sig string
+	// ID is the selector, the first 4 bytes of the Keccak256 hash of Sig.
+	ID types.Selector
+}
+
+// NewMethod creates a method and computes its signature and selector.
+func NewMethod(name, rawName, stateMutability string, inputs, outputs Arguments) Method {
+	m := Method{
+		Name:            name,
+		RawName:         rawName,
+		StateMutability: stateMutability,
+		Inputs:          inputs,
+		Outputs:         outputs,
+		Sig:             signature(rawName, inputs),
+	}
+	copy(m.ID[:], crypto.Keccak256([]byte(m.Sig)))
+	return m
+}
+
+// String returns the method declaration in Solidity syntax.
+func (m Method) String() string {
+	decl := fmt.Sprintf("function %v(%v)", m.RawName, declaration(m.Inputs))
+	if m.StateMutability != "" && m.StateMutability != "nonpayable" {
+		decl += " " + m.StateMutability
+	}
+	if len(m.Outputs) > 0 {
+		decl += fmt.Sprintf(" returns(%v)", declaration(m.Outputs))
+	}
+	return decl
+}
+
+// Event is a contract event.
+type Event struct {
+	// Name is the key of the event in ABI.Events. Overloaded events get a
+	// numeric suffix, starting at 0 for the second one.
+	Name string
+	// RawName is the name of the event in the contract.
+	RawName   string
+	Anonymous bool
+	Inputs    Arguments
+
+	// Sig is the canonical signature, e.g. "Transfer(address,address,uint256)".
+	Sig string
+	// ID is the Keccak256 hash of Sig, the first topic of non-anonymous
+	// event logs.
+	ID common.Hash
+}
+
+// NewEvent creates an event and computes its signature and topic.
+func NewEvent(name, rawName string, anonymous bool, inputs Arguments) Event {
+	sig := signature(rawName, inputs)
+	return Event{
+		Name:      name,
+		RawName:   rawName,
+		Anonymous: anonymous,
+		Inputs:    inputs,
+		Sig:       sig,
+		ID:        crypto.Keccak256Hash([]byte(sig)),
+	}
+}
+
+// String returns the event declaration in Solidity syntax.
+func (e Event) String() string {
+	decl := fmt.Sprintf("event %v(%v)", e.RawName, declaration(e.Inputs))
+	if e.Anonymous {
+		decl += " anonymous"
+	}
+	return decl
+}
+
+// DecodeLog decodes the arguments of the event from a log into a map keyed
+// by argument name. Indexed arguments of dynamic types are only stored as
+// the hash of their encoding and decode as common.Hash.
+func (e Event) DecodeLog(log *types.Log) (map[string]interface{}, error) {
+	topics := log.Topics
+	if !e.Anonymous {
+		if len(topics) == 0 || topics[0] != e.ID {
+			return nil, fmt.Errorf("abi: log is not a %s event", e.RawName)
+		}
+		topics = topics[1:]
+	}
+	out := make(map[string]interface{})
+	if err := e.Inputs.UnpackIntoMap(out, log.Data); err != nil {
+		return nil, err
+	}
+	for _, arg := range e.Inputs {
+		if !arg.Indexed {
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("abi: missing topic for indexed argument %s", arg.Name)
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		switch arg.Type.T {
+		case StringTy, BytesTy, SliceTy, ArrayTy, TupleTy:
+			out[arg.Name] = topic
+		default:
+			v, err := decode(&arg.Type, topic[:])
+			if err != nil {
+				return nil, fmt.Errorf("abi: indexed argument %s: %v", arg.Name, err)
+			}
+			out[arg.Name] = v.Interface()
+		}
+	}
+	if len(topics) != 0 {
+		return nil, fmt.Errorf("abi: %d unexpected topics in %s event", len(topics), e.RawName)
+	}
+	return out, nil
+}
+
+func signature(name string, args Arguments) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type.String()
+	}
+	return fmt.Sprintf("%v(%v)", name, strings.Join(types, ","))
+}
+
+func declaration(args Arguments) string {
+	decls := make([]string, len(args))
+	for i, arg := range args {
+		decls[i] = arg.Type.String()
+		if arg.Indexed {
+			decls[i] += " indexed"
+		}
+		if arg.Name != "" {
+			decls[i] += " " + arg.Name
+		}
+	}
+	return strings.Join(decls, ", ")
+}
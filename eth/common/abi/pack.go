@@ -0,0 +1,217 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/common/math"
+)
+
+var bigT = reflect.TypeOf((*big.Int)(nil))
+
+// pack encodes v as a value of type t. Dynamic values are encoded as their
+// content; the caller places them behind an offset.
+func (t Type) pack(v reflect.Value) ([]byte, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil, typeErr(t, v)
+	}
+	switch t.T {
+	case SliceTy, ArrayTy:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, typeErr(t, v)
+		}
+		if t.T == ArrayTy && v.Len() != t.Size {
+			return nil, fmt.Errorf("abi: cannot use %d elements as %v", v.Len(), t)
+		}
+		types := make([]*Type, v.Len())
+		values := make([]reflect.Value, v.Len())
+		for i := range types {
+			types[i], values[i] = t.Elem, v.Index(i)
+		}
+		enc, err := packTuple(types, values)
+		if err != nil {
+			return nil, err
+		}
+		if t.T == SliceTy {
+			enc = append(packNum(uint64(v.Len())), enc...)
+		}
+		return enc, nil
+
+	case TupleTy:
+		values, err := t.tupleValues(v)
+		if err != nil {
+			return nil, err
+		}
+		return packTuple(t.TupleElems, values)
+
+	case StringTy:
+		if v.Kind() != reflect.String {
+			return nil, typeErr(t, v)
+		}
+		return packBytes([]byte(v.String())), nil
+
+	case BytesTy:
+		if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, typeErr(t, v)
+		}
+		return packBytes(v.Bytes()), nil
+
+	case FixedBytesTy, FunctionTy:
+		if (v.Kind() != reflect.Array && v.Kind() != reflect.Slice) || v.Type().Elem().Kind() != reflect.Uint8 || v.Len() != t.Size {
+			return nil, typeErr(t, v)
+		}
+		enc := make([]byte, 32)
+		reflect.Copy(reflect.ValueOf(enc[:t.Size]), v)
+		return enc, nil
+
+	case AddressTy:
+		if v.Type() != reflect.TypeOf(common.Address{}) {
+			return nil, typeErr(t, v)
+		}
+		return common.LeftPadBytes(v.Interface().(common.Address).Bytes(), 32), nil
+
+	case BoolTy:
+		if v.Kind() != reflect.Bool {
+			return nil, typeErr(t, v)
+		}
+		if v.Bool() {
+			return packNum(1), nil
+		}
+		return packNum(0), nil
+
+	case IntTy, UintTy:
+		n, ok := toBig(v)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		if !t.fits(n) {
+			return nil, fmt.Errorf("abi: %v out of range for %v", n, t)
+		}
+		return math.PaddedBigBytes(math.U256(new(big.Int).Set(n)), 32), nil
+	}
+	return nil, fmt.Errorf("abi: cannot pack %v", t)
+}
+
+// tupleValues returns the components of a tuple value, given as a struct
+// with fields named as in GetType or as a slice of values in order.
+func (t Type) tupleValues(v reflect.Value) ([]reflect.Value, error) {
+	values := make([]reflect.Value, len(t.TupleElems))
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := range values {
+			name := t.goType.Field(i).Name
+			f := v.FieldByName(name)
+			if !f.IsValid() {
+				return nil, fmt.Errorf("abi: field %s missing in %v", name, v.Type())
+			}
+			values[i] = f
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Len() != len(values) {
+			return nil, fmt.Errorf("abi: cannot use %d values as %v", v.Len(), t)
+		}
+		for i := range values {
+			values[i] = v.Index(i)
+		}
+	default:
+		return nil, typeErr(t, v)
+	}
+	return values, nil
+}
+
+// packTuple encodes values as a sequence of the given types: static values
+// in the head, dynamic ones in the tail behind offsets relative to the
+// start of the sequence.
+func packTuple(types []*Type, values []reflect.Value) ([]byte, error) {
+	headLen := 0
+	for _, t := range types {
+		headLen += t.headSize()
+	}
+	var head, tail []byte
+	for i, t := range types {
+		enc, err := t.pack(values[i])
+		if err != nil {
+			return nil, err
+		}
+		if t.isDynamic() {
+			head = append(head, packNum(uint64(headLen+len(tail)))...)
+			tail = append(tail, enc...)
+		} else {
+			head = append(head, enc...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+// packBytes encodes the length of b followed by b right padded to a
+// multiple of 32 bytes.
+func packBytes(b []byte) []byte {
+	enc := packNum(uint64(len(b)))
+	enc = append(enc, b...)
+	if rem := len(b) % 32; rem != 0 {
+		enc = append(enc, make([]byte, 32-rem)...)
+	}
+	return enc
+}
+
+func packNum(n uint64) []byte {
+	return math.PaddedBigBytes(new(big.Int).SetUint64(n), 32)
+}
+
+// fits reports whether n is in the range of the integer type t.
+func (t Type) fits(n *big.Int) bool {
+	if t.T == UintTy {
+		return n.Sign() >= 0 && n.BitLen() <= t.Size
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	return n.Cmp(limit) < 0 && n.Cmp(limit.Neg(limit)) >= 0
+}
+
+// toBig converts Go integers and big integers to a *big.Int.
+func toBig(v reflect.Value) (*big.Int, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(v.Uint()), true
+	case reflect.Struct:
+		if v.Type() == bigT.Elem() && v.CanAddr() {
+			return v.Addr().Interface().(*big.Int), true
+		}
+	}
+	return nil, false
+}
+
+// indirect dereferences pointers and interfaces.
+func indirect(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func typeErr(t Type, v reflect.Value) error {
+	if !v.IsValid() {
+		return fmt.Errorf("abi: cannot use nil as %v", t)
+	}
+	return fmt.Errorf("abi: cannot use %v as %v", v.Type(), t)
+}
@@ -0,0 +1,269 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+func mustArgs(t *testing.T, typeNames ...string) Arguments {
+	t.Helper()
+	args := make(Arguments, len(typeNames))
+	for i, name := range typeNames {
+		typ, err := NewType(name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args[i] = Argument{Type: typ}
+	}
+	return args
+}
+
+// words joins 32 byte words given in hex, left padding numbers and right
+// padding strings prefixed with "s:".
+func words(ws ...string) []byte {
+	var out []byte
+	for _, w := range ws {
+		if strings.HasPrefix(w, "s:") {
+			out = append(out, common.RightPadBytes(common.FromHex(w[2:]), 32)...)
+		} else {
+			out = append(out, common.LeftPadBytes(common.FromHex(w), 32)...)
+		}
+	}
+	return out
+}
+
+// Examples from the Solidity ABI specification.
+func TestPackSpecExamples(t *testing.T) {
+	tests := []struct {
+		name     string
+		types    []string
+		args     []interface{}
+		selector string
+		want     []byte
+	}{
+		{
+			"baz", []string{"uint32", "bool"},
+			[]interface{}{uint32(69), true},
+			"cdcd77c0", words("45", "01"),
+		},
+		{
+			"bar", []string{"bytes3[2]"},
+			[]interface{}{[2][3]byte{{'a', 'b', 'c'}, {'d', 'e', 'f'}}},
+			"fce353f6", words("s:616263", "s:646566"),
+		},
+		{
+			"sam", []string{"bytes", "bool", "uint256[]"},
+			[]interface{}{[]byte("dave"), true, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}},
+			"a5643bf2", words("60", "01", "a0", "04", "s:64617665", "03", "01", "02", "03"),
+		},
+		{
+			"f", []string{"uint256", "uint32[]", "bytes10", "bytes"},
+			[]interface{}{big.NewInt(0x123), []uint32{0x456, 0x789}, [10]byte{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}, []byte("Hello, world!")},
+			"8be65246", words("0123", "80", "s:31323334353637383930", "e0", "02", "0456", "0789", "0d", "s:48656c6c6f2c20776f726c6421"),
+		},
+		{
+			"g", []string{"uint256[][]", "string[]"},
+			[]interface{}{
+				[][]*big.Int{{big.NewInt(1), big.NewInt(2)}, {big.NewInt(3)}},
+				[]string{"one", "two", "three"},
+			},
+			"2289b18c", words("40", "0140", "02", "40", "a0", "02", "01", "02", "01", "03",
+				"03", "60", "a0", "e0", "03", "s:6f6e65", "03", "s:74776f", "05", "s:7468726565"),
+		},
+	}
+	for _, test := range tests {
+		args := mustArgs(t, test.types...)
+		method := NewMethod(test.name, test.name, "", args, nil)
+		if got := common.Bytes2Hex(method.ID[:]); got != test.selector {
+			t.Errorf("%s: selector %s, want %s", method.Sig, got, test.selector)
+		}
+		enc, err := args.Pack(test.args...)
+		if err != nil {
+			t.Fatalf("%s: %v", method.Sig, err)
+		}
+		if !bytes.Equal(enc, test.want) {
+			t.Errorf("%s: encoding mismatch\ngot  %x\nwant %x", method.Sig, enc, test.want)
+		}
+		values, err := args.Unpack(enc)
+		if err != nil {
+			t.Fatalf("%s: unpack: %v", method.Sig, err)
+		}
+		if !reflect.DeepEqual(values, test.args) {
+			t.Errorf("%s: unpacked %v, want %v", method.Sig, values, test.args)
+		}
+	}
+}
+
+func TestPackIntegers(t *testing.T) {
+	tests := []struct {
+		typ  string
+		arg  interface{}
+		want []byte
+		out  interface{}
+	}{
+		{"uint8", uint8(255), words("ff"), uint8(255)},
+		{"uint8", 7, words("07"), uint8(7)},
+		{"int8", int8(-1), words(strings.Repeat("ff", 32)), int8(-1)},
+		{"int64", int64(-2), words(strings.Repeat("ff", 31) + "fe"), int64(-2)},
+		{"uint24", big.NewInt(0xabcdef), words("abcdef"), big.NewInt(0xabcdef)},
+		{"int256", big.NewInt(-1), words(strings.Repeat("ff", 32)), big.NewInt(-1)},
+		{"uint", new(big.Int).Lsh(big.NewInt(1), 255), words("80" + strings.Repeat("00", 31)), new(big.Int).Lsh(big.NewInt(1), 255)},
+	}
+	for _, test := range tests {
+		args := mustArgs(t, test.typ)
+		enc, err := args.Pack(test.arg)
+		if err != nil {
+			t.Fatalf("%s %v: %v", test.typ, test.arg, err)
+		}
+		if !bytes.Equal(enc, test.want) {
+			t.Errorf("%s %v: got %x, want %x", test.typ, test.arg, enc, test.want)
+		}
+		values, err := args.Unpack(enc)
+		if err != nil {
+			t.Fatalf("%s %v: unpack: %v", test.typ, test.arg, err)
+		}
+		if !reflect.DeepEqual(values[0], test.out) {
+			t.Errorf("%s %v: unpacked %v (%T)", test.typ, test.arg, values[0], values[0])
+		}
+	}
+
+	for _, test := range []struct {
+		typ string
+		arg interface{}
+	}{
+		{"uint8", 256},
+		{"uint256", big.NewInt(-1)},
+		{"int8", 128},
+		{"int8", -129},
+		{"uint32", "1"},
+	} {
+		if _, err := mustArgs(t, test.typ).Pack(test.arg); err == nil {
+			t.Errorf("%s: packed invalid value %v", test.typ, test.arg)
+		}
+	}
+	// Values out of range of the type must not decode.
+	if _, err := mustArgs(t, "uint8").Unpack(words("0100")); err == nil {
+		t.Error("unpacked out of range uint8")
+	}
+	if _, err := mustArgs(t, "bool").Unpack(words("02")); err != errBadBool {
+		t.Errorf("got error %v, want %v", err, errBadBool)
+	}
+}
+
+// Nil arguments must be rejected with an error for every static type.
+func TestPackNil(t *testing.T) {
+	for _, typ := range []string{"address", "bool", "uint8", "uint256", "int64", "bytes4", "bytes32", "function", "uint8[2]", "string", "bytes", "uint256[]"} {
+		for _, arg := range []interface{}{nil, (*common.Address)(nil), (*big.Int)(nil)} {
+			if _, err := mustArgs(t, typ).Pack(arg); err == nil {
+				t.Errorf("%s: packed %#v", typ, arg)
+			}
+		}
+	}
+	// Nil tuple components are rejected as well.
+	tuple, err := NewType("tuple", []ArgumentMarshaling{{Name: "a", Type: "address"}, {Name: "b", Type: "uint256"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := Arguments{{Type: tuple}}
+	for _, arg := range []interface{}{nil, []interface{}{nil, big.NewInt(1)}, []interface{}{common.Address{}, nil}} {
+		if _, err := args.Pack(arg); err == nil {
+			t.Errorf("tuple: packed %#v", arg)
+		}
+	}
+}
+
+func TestPackTuple(t *testing.T) {
+	typ, err := NewType("tuple[]", []ArgumentMarshaling{
+		{Name: "owner", Type: "address"},
+		{Name: "tags", Type: "string[]"},
+		{Name: "amount_in", Type: "uint64"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ.String() != "(address,string[],uint64)[]" {
+		t.Fatalf("wrong type string %s", typ)
+	}
+	args := Arguments{{Name: "orders", Type: typ}, {Name: "n", Type: mustArgs(t, "uint8")[0].Type}}
+
+	type order struct {
+		Owner    common.Address
+		Tags     []string
+		AmountIn uint64
+	}
+	orders := []order{
+		{common.HexToAddress("0x01"), []string{"a", "bc"}, 5},
+		{common.HexToAddress("0x02"), nil, 6},
+	}
+	enc, err := args.Pack(orders, uint8(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Positional values encode the same.
+	positional := [][]interface{}{
+		{orders[0].Owner, orders[0].Tags, orders[0].AmountIn},
+		{orders[1].Owner, []string{}, orders[1].AmountIn},
+	}
+	if enc2, err := args.Pack(positional, uint8(9)); err != nil || !bytes.Equal(enc, enc2) {
+		t.Fatalf("positional tuple encoding differs: %v", err)
+	}
+
+	values, err := args.Unpack(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := reflect.ValueOf(values[0])
+	if decoded.Len() != 2 || values[1] != uint8(9) {
+		t.Fatalf("unexpected values %v", values)
+	}
+	for i, o := range orders {
+		elem := decoded.Index(i)
+		if elem.FieldByName("Owner").Interface() != o.Owner || elem.FieldByName("AmountIn").Uint() != o.AmountIn || elem.FieldByName("Tags").Len() != len(o.Tags) {
+			t.Errorf("order %d decoded as %+v", i, elem.Interface())
+		}
+	}
+}
+
+func TestUnpackMalformed(t *testing.T) {
+	args := mustArgs(t, "string", "uint256[]")
+	enc, err := args.Pack("hello", []*big.Int{big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < len(enc); n += 16 {
+		if _, err := args.Unpack(enc[:n]); err == nil {
+			t.Errorf("unpacked %d of %d bytes", n, len(enc))
+		}
+	}
+	// Offsets and lengths pointing outside of the data.
+	for _, data := range [][]byte{
+		words("ff", "40"),
+		words("40", "60", "ff"),
+		words("40", "80", "05", "s:68656c6c6f", "ffffffff"),
+	} {
+		if _, err := args.Unpack(data); err == nil {
+			t.Errorf("unpacked malformed data %x", data)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+// Type kinds.
+const (
+	IntTy byte = iota
+	UintTy
+	BoolTy
+	StringTy
+	SliceTy
+	ArrayTy
+	TupleTy
+	AddressTy
+	FixedBytesTy
+	BytesTy
+	FunctionTy
+)
+
+// Type is a Solidity ABI type.
+type Type struct {
+	T    byte  // kind
+	Size int   // bit size of integers, byte size of fixed bytes, length of arrays
+	Elem *Type // element type of arrays and slices
+
+	TupleElems    []*Type  // component types of tuples
+	TupleRawNames []string // component names of tuples, as in the ABI
+
+	stringKind string       // canonical type string, used in signatures
+	goType     reflect.Type // Go type of decoded values
+}
+
+// NewType parses a Solidity type such as "uint256", "bytes32[]" or
+// "tuple[2]". The components describe the fields of tuple types and are
+// ignored otherwise.
+func NewType(t string, components []ArgumentMarshaling) (Type, error) {
+	if strings.HasSuffix(t, "]") {
+		open := strings.LastIndex(t, "[")
+		if open < 0 {
+			return Type{}, fmt.Errorf("abi: invalid type %q", t)
+		}
+		elem, err := NewType(t[:open], components)
+		if err != nil {
+			return Type{}, err
+		}
+		typ := Type{Elem: &elem}
+		if n := t[open+1 : len(t)-1]; n == "" {
+			typ.T = SliceTy
+			typ.stringKind = elem.stringKind + "[]"
+			typ.goType = reflect.SliceOf(elem.goType)
+		} else {
+			size, err := strconv.Atoi(n)
+			if err != nil || size <= 0 {
+				return Type{}, fmt.Errorf("abi: invalid array size in %q", t)
+			}
+			typ.T, typ.Size = ArrayTy, size
+			typ.stringKind = elem.stringKind + "[" + n + "]"
+			typ.goType = reflect.ArrayOf(size, elem.goType)
+		}
+		return typ, nil
+	}
+
+	switch {
+	case t == "bool":
+		return Type{T: BoolTy, stringKind: t, goType: reflect.TypeOf(false)}, nil
+	case t == "address":
+		return Type{T: AddressTy, Size: 20, stringKind: t, goType: reflect.TypeOf(common.Address{})}, nil
+	case t == "string":
+		return Type{T: StringTy, stringKind: t, goType: reflect.TypeOf("")}, nil
+	case t == "bytes":
+		return Type{T: BytesTy, stringKind: t, goType: reflect.TypeOf([]byte(nil))}, nil
+	case t == "function":
+		return Type{T: FunctionTy, Size: 24, stringKind: t, goType: reflect.TypeOf([24]byte{})}, nil
+	case t == "tuple":
+		return newTupleType(components)
+	case strings.HasPrefix(t, "bytes"):
+		size, err := strconv.Atoi(t[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return Type{}, fmt.Errorf("abi: invalid type %q", t)
+		}
+		return Type{T: FixedBytesTy, Size: size, stringKind: t, goType: reflect.ArrayOf(size, reflect.TypeOf(byte(0)))}, nil
+	case strings.HasPrefix(t, "uint"), strings.HasPrefix(t, "int"):
+		typ := Type{T: IntTy}
+		bits := strings.TrimPrefix(t, "int")
+		if strings.HasPrefix(t, "uint") {
+			typ.T, bits = UintTy, strings.TrimPrefix(t, "uint")
+		}
+		if bits == "" {
+			typ.Size = 256
+		} else {
+			size, err := strconv.Atoi(bits)
+			if err != nil || size < 8 || size > 256 || size%8 != 0 {
+				return Type{}, fmt.Errorf("abi: invalid type %q", t)
+			}
+			typ.Size = size
+		}
+		typ.stringKind = strings.TrimSuffix(t, bits) + strconv.Itoa(typ.Size)
+		typ.goType = integerType(typ.T == UintTy, typ.Size)
+		return typ, nil
+	}
+	return Type{}, fmt.Errorf("abi: unsupported type %q", t)
+}
+
+func newTupleType(components []ArgumentMarshaling) (Type, error) {
+	if len(components) == 0 {
+		return Type{}, errors.New("abi: tuple without components")
+	}
+	typ := Type{T: TupleTy}
+	fields := make([]reflect.StructField, len(components))
+	kinds := make([]string, len(components))
+	used := make(map[string]bool)
+	for i, c := range components {
+		elem, err := NewType(c.Type, c.Components)
+		if err != nil {
+			return Type{}, err
+		}
+		name := ToCamelCase(c.Name)
+		if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+			name = fmt.Sprintf("Field%d", i)
+		}
+		if used[name] {
+			return Type{}, fmt.Errorf("abi: duplicate tuple field %q", name)
+		}
+		used[name] = true
+		fields[i] = reflect.StructField{Name: name, Type: elem.goType, Tag: reflect.StructTag(fmt.Sprintf(`json:"%s"`, c.Name))}
+		typ.TupleElems = append(typ.TupleElems, &elem)
+		typ.TupleRawNames = append(typ.TupleRawNames, c.Name)
+		kinds[i] = elem.stringKind
+	}
+	typ.stringKind = "(" + strings.Join(kinds, ",") + ")"
+	typ.goType = reflect.StructOf(fields)
+	return typ, nil
+}
+
+func integerType(unsigned bool, size int) reflect.Type {
+	switch {
+	case unsigned && size == 8:
+		return reflect.TypeOf(uint8(0))
+	case unsigned && size == 16:
+		return reflect.TypeOf(uint16(0))
+	case unsigned && size == 32:
+		return reflect.TypeOf(uint32(0))
+	case unsigned && size == 64:
+		return reflect.TypeOf(uint64(0))
+	case !unsigned && size == 8:
+		return reflect.TypeOf(int8(0))
+	case !unsigned && size == 16:
+		return reflect.TypeOf(int16(0))
+	case !unsigned && size == 32:
+		return reflect.TypeOf(int32(0))
+	case !unsigned && size == 64:
+		return reflect.TypeOf(int64(0))
+	}
+	return reflect.TypeOf((*big.Int)(nil))
+}
+
+// String returns the canonical type string as used in signatures.
+func (t Type) String() string { return t.stringKind }
+
+// GetType returns the Go type of decoded values: fixed size Go integers for
+// 8, 16, 32 and 64 bit integers and *big.Int for others, common.Address,
+// []byte for bytes, byte arrays for bytesN, Go arrays and slices, and
+// structs for tuples.
+func (t Type) GetType() reflect.Type { return t.goType }
+
+// isDynamic reports whether values of the type are encoded out of line,
+// behind an offset.
+func (t Type) isDynamic() bool {
+	switch t.T {
+	case StringTy, BytesTy, SliceTy:
+		return true
+	case ArrayTy:
+		return t.Elem.isDynamic()
+	case TupleTy:
+		for _, elem := range t.TupleElems {
+			if elem.isDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize returns the size of the type's encoding within the head of an
+// enclosing tuple.
+func (t Type) headSize() int {
+	if t.isDynamic() {
+		return 32
+	}
+	switch t.T {
+	case ArrayTy:
+		return t.Size * t.Elem.headSize()
+	case TupleTy:
+		size := 0
+		for _, elem := range t.TupleElems {
+			size += elem.headSize()
+		}
+		return size
+	}
+	return 32
+}
+
+// ToCamelCase converts an under_score or camelCase name to CamelCase.
+func ToCamelCase(input string) string {
+	parts := strings.Split(input, "_")
+	for i, s := range parts {
+		if len(s) > 0 {
+			parts[i] = strings.ToUpper(s[:1]) + s[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
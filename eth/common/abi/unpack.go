@@ -0,0 +1,172 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+var (
+	errShortData = errors.New("abi: data too short")
+	errBadBool   = errors.New("abi: improperly encoded boolean value")
+
+	tt256 = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// decodeAt decodes a value of type t whose head is at pos in data, the
+// encoding of the enclosing sequence. Dynamic values are found through the
+// offset in their head.
+func decodeAt(t *Type, data []byte, pos int) (reflect.Value, error) {
+	if !t.isDynamic() {
+		if pos+t.headSize() > len(data) {
+			return reflect.Value{}, errShortData
+		}
+		return decode(t, data[pos:])
+	}
+	offset, err := readLength(data, pos)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if offset > len(data) {
+		return reflect.Value{}, errShortData
+	}
+	return decode(t, data[offset:])
+}
+
+// decode decodes a value of type t from the start of data.
+func decode(t *Type, data []byte) (reflect.Value, error) {
+	switch t.T {
+	case StringTy, BytesTy:
+		n, err := readLength(data, 0)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if 32+n > len(data) {
+			return reflect.Value{}, errShortData
+		}
+		b := common.CopyBytes(data[32 : 32+n])
+		if t.T == StringTy {
+			return reflect.ValueOf(string(b)), nil
+		}
+		return reflect.ValueOf(b), nil
+
+	case SliceTy:
+		n, err := readLength(data, 0)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		// Every element takes at least one word; reject lengths the data
+		// cannot hold before allocating.
+		if n > (len(data)-32)/32 {
+			return reflect.Value{}, errShortData
+		}
+		v := reflect.MakeSlice(t.goType, n, n)
+		return v, decodeSeq(repeatType(t.Elem, n), data[32:], v.Index)
+
+	case ArrayTy:
+		v := reflect.New(t.goType).Elem()
+		return v, decodeSeq(repeatType(t.Elem, t.Size), data, v.Index)
+
+	case TupleTy:
+		v := reflect.New(t.goType).Elem()
+		return v, decodeSeq(t.TupleElems, data, v.Field)
+	}
+
+	if len(data) < 32 {
+		return reflect.Value{}, errShortData
+	}
+	word := data[:32]
+	switch t.T {
+	case IntTy, UintTy:
+		return readInteger(t, word)
+	case BoolTy:
+		for _, b := range word[:31] {
+			if b != 0 {
+				return reflect.Value{}, errBadBool
+			}
+		}
+		if word[31] > 1 {
+			return reflect.Value{}, errBadBool
+		}
+		return reflect.ValueOf(word[31] == 1), nil
+	case AddressTy:
+		return reflect.ValueOf(common.BytesToAddress(word[12:])), nil
+	case FixedBytesTy, FunctionTy:
+		v := reflect.New(t.goType).Elem()
+		reflect.Copy(v, reflect.ValueOf(word[:t.Size]))
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("abi: cannot unpack %v", t)
+}
+
+// decodeSeq decodes a sequence of values of the given types from data and
+// stores them with set, which returns the destination of value i.
+func decodeSeq(types []*Type, data []byte, set func(int) reflect.Value) error {
+	pos := 0
+	for i, t := range types {
+		v, err := decodeAt(t, data, pos)
+		if err != nil {
+			return err
+		}
+		set(i).Set(v)
+		pos += t.headSize()
+	}
+	return nil
+}
+
+func repeatType(t *Type, n int) []*Type {
+	types := make([]*Type, n)
+	for i := range types {
+		types[i] = t
+	}
+	return types
+}
+
+// readLength reads a length or offset word at pos.
+func readLength(data []byte, pos int) (int, error) {
+	if pos+32 > len(data) {
+		return 0, errShortData
+	}
+	n := new(big.Int).SetBytes(data[pos : pos+32])
+	if !n.IsInt64() || n.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("abi: length or offset %v out of bounds", n)
+	}
+	return int(n.Int64()), nil
+}
+
+// readInteger decodes an integer word, which must be in range of t.
+func readInteger(t *Type, word []byte) (reflect.Value, error) {
+	n := new(big.Int).SetBytes(word)
+	if t.T == IntTy && word[0]&0x80 != 0 {
+		n.Sub(n, tt256)
+	}
+	if !t.fits(n) {
+		return reflect.Value{}, fmt.Errorf("abi: value %v out of range for %v", n, t)
+	}
+	switch kind := t.goType.Kind(); kind {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.ValueOf(n.Uint64()).Convert(t.goType), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.ValueOf(n.Int64()).Convert(t.goType), nil
+	}
+	return reflect.ValueOf(n), nil
+}
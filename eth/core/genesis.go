@@ -0,0 +1,211 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package core implements the genesis specification of a chain.
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/common/hexutil"
+	"github.com/arcology-network/3rd-party/eth/common/math"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+	"github.com/arcology-network/3rd-party/eth/params"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+//go:generate gencodec -type Genesis -field-override genesisSpecMarshaling -out gen_genesis.go
+//go:generate gencodec -type GenesisAccount -field-override genesisAccountMarshaling -out gen_genesis_account.go
+
+// emptyCodeHash is the code hash of accounts without code.
+var emptyCodeHash = crypto.Keccak256Hash(nil)
+
+// Genesis specifies the header fields and the state of a genesis block.
+type Genesis struct {
+	Config     *params.ChainConfig `json:"config"`
+	Nonce      uint64              `json:"nonce"`
+	Timestamp  uint64              `json:"timestamp"`
+	ExtraData  []byte              `json:"extraData"`
+	GasLimit   uint64              `json:"gasLimit"   gencodec:"required"`
+	Difficulty *big.Int            `json:"difficulty" gencodec:"required"`
+	Mixhash    common.Hash         `json:"mixHash"`
+	Coinbase   common.Address      `json:"coinbase"`
+	Alloc      GenesisAlloc        `json:"alloc"      gencodec:"required"`
+
+	// These fields are used for consensus tests. Please don't use them
+	// in actual genesis blocks.
+	Number     uint64      `json:"number"`
+	GasUsed    uint64      `json:"gasUsed"`
+	ParentHash common.Hash `json:"parentHash"`
+	BaseFee    *big.Int    `json:"baseFeePerGas"`
+}
+
+// GenesisAlloc specifies the initial state that is part of the genesis block.
+type GenesisAlloc map[common.Address]GenesisAccount
+
+// UnmarshalJSON decodes an alloc keyed by addresses with or without 0x
+// prefix.
+func (ga *GenesisAlloc) UnmarshalJSON(data []byte) error {
+	m := make(map[common.UnprefixedAddress]GenesisAccount)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*ga = make(GenesisAlloc, len(m))
+	for addr, a := range m {
+		(*ga)[common.Address(addr)] = a
+	}
+	return nil
+}
+
+// Root returns the root hash of the state trie holding the accounts of ga.
+func (ga GenesisAlloc) Root() common.Hash {
+	accounts := make(map[common.Hash][]byte, len(ga))
+	for addr, account := range ga {
+		accounts[crypto.Keccak256Hash(addr[:])] = account.encode()
+	}
+	return types.HashedTrieRoot(accounts)
+}
+
+// GenesisAccount is an account in the state of the genesis block.
+type GenesisAccount struct {
+	Code       []byte                      `json:"code,omitempty"`
+	Storage    map[common.Hash]common.Hash `json:"storage,omitempty"`
+	Balance    *big.Int                    `json:"balance" gencodec:"required"`
+	Nonce      uint64                      `json:"nonce,omitempty"`
+	PrivateKey []byte                      `json:"secretKey,omitempty"` // for tests
+}
+
+// stateAccount is the consensus encoding of an account in the state trie.
+type stateAccount struct {
+	Nonce    uint64
+	Balance  *big.Int
+	Root     common.Hash
+	CodeHash []byte
+}
+
+// encode returns the encoding of the account in the state trie.
+func (a *GenesisAccount) encode() []byte {
+	account := stateAccount{
+		Nonce:    a.Nonce,
+		Balance:  a.Balance,
+		Root:     a.storageRoot(),
+		CodeHash: emptyCodeHash[:],
+	}
+	if account.Balance == nil {
+		account.Balance = new(big.Int)
+	}
+	if len(a.Code) > 0 {
+		account.CodeHash = crypto.Keccak256(a.Code)
+	}
+	enc, _ := rlp.EncodeToBytes(&account)
+	return enc
+}
+
+// storageRoot returns the root hash of the storage trie of the account.
+// Slots holding zero are not part of the trie.
+func (a *GenesisAccount) storageRoot() common.Hash {
+	slots := make(map[common.Hash][]byte, len(a.Storage))
+	for key, value := range a.Storage {
+		if value == (common.Hash{}) {
+			continue
+		}
+		enc, _ := rlp.EncodeToBytes(new(big.Int).SetBytes(value[:]))
+		slots[crypto.Keccak256Hash(key[:])] = enc
+	}
+	return types.HashedTrieRoot(slots)
+}
+
+// field type overrides for gencodec
+type genesisSpecMarshaling struct {
+	Nonce      math.HexOrDecimal64
+	Timestamp  math.HexOrDecimal64
+	ExtraData  hexutil.Bytes
+	GasLimit   math.HexOrDecimal64
+	GasUsed    math.HexOrDecimal64
+	Number     math.HexOrDecimal64
+	Difficulty *math.HexOrDecimal256
+	BaseFee    *math.HexOrDecimal256
+	Alloc      map[common.UnprefixedAddress]GenesisAccount
+}
+
+type genesisAccountMarshaling struct {
+	Code       hexutil.Bytes
+	Balance    *math.HexOrDecimal256
+	Nonce      math.HexOrDecimal64
+	Storage    map[storageJSON]storageJSON
+	PrivateKey hexutil.Bytes
+}
+
+// storageJSON represents a 256 bit byte array, but allows less than 256 bits when
+// unmarshaling from hex.
+type storageJSON common.Hash
+
+func (h *storageJSON) UnmarshalText(text []byte) error {
+	text = bytes.TrimPrefix(text, []byte("0x"))
+	if len(text) > 64 {
+		return fmt.Errorf("too many hex characters in storage key/value %q", text)
+	}
+	offset := len(h) - len(text)/2 // pad on the left
+	if _, err := hex.Decode(h[offset:], text); err != nil {
+		return fmt.Errorf("invalid hex storage key/value %q", text)
+	}
+	return nil
+}
+
+func (h storageJSON) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(h[:]).MarshalText()
+}
+
+// ToBlock returns the genesis block described by g. The state root is
+// derived from the alloc, and the transaction, receipt and uncle roots are
+// those of an empty block. A zero gas limit and a missing difficulty are
+// replaced by the protocol defaults. If London is active at genesis, the
+// header carries the base fee of g, or params.InitialBaseFee if unset.
+func (g *Genesis) ToBlock() *types.Block {
+	head := &types.Header{
+		Number:     new(big.Int).SetUint64(g.Number),
+		Nonce:      types.EncodeNonce(g.Nonce),
+		Time:       new(big.Int).SetUint64(g.Timestamp),
+		ParentHash: g.ParentHash,
+		Extra:      g.ExtraData,
+		GasLimit:   g.GasLimit,
+		GasUsed:    g.GasUsed,
+		Difficulty: g.Difficulty,
+		MixDigest:  g.Mixhash,
+		Coinbase:   g.Coinbase,
+		Root:       g.Alloc.Root(),
+	}
+	if g.GasLimit == 0 {
+		head.GasLimit = params.GenesisGasLimit
+	}
+	if g.Difficulty == nil {
+		head.Difficulty = params.GenesisDifficulty
+	}
+	if g.Config != nil && g.Config.IsLondon(common.Big0) {
+		if g.BaseFee != nil {
+			head.BaseFee = g.BaseFee
+		} else {
+			head.BaseFee = new(big.Int).SetUint64(params.InitialBaseFee)
+		}
+	}
+	return types.NewBlock(head, nil, nil, nil)
+}
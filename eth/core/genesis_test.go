@@ -0,0 +1,221 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+	"github.com/arcology-network/3rd-party/eth/params"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+func TestGenesisToBlock(t *testing.T) {
+	genesis := &Genesis{
+		Config: &params.ChainConfig{HomesteadBlock: big.NewInt(3)},
+		Alloc: GenesisAlloc{
+			{1}: {Balance: big.NewInt(1), Storage: map[common.Hash]common.Hash{{1}: {1}}},
+		},
+	}
+	block := genesis.ToBlock()
+	// The same alloc is assembled by hand in TestGenesisHandComputed.
+	if want := common.HexToHash("0x89c99d90b79719238d2645c7642f2c9295246e80775b38cfd162b696817fbd50"); block.Hash() != want {
+		t.Errorf("wrong genesis hash %x, want %x", block.Hash(), want)
+	}
+	if block.GasLimit() != params.GenesisGasLimit || block.Difficulty().Cmp(params.GenesisDifficulty) != 0 {
+		t.Errorf("defaults not applied: gas limit %d, difficulty %v", block.GasLimit(), block.Difficulty())
+	}
+	if block.TxHash() != types.EmptyRootHash || block.ReceiptHash() != types.EmptyRootHash || block.UncleHash() != types.EmptyUncleHash {
+		t.Error("wrong roots of empty block")
+	}
+
+	// Zero storage slots are not part of the state.
+	genesis.Alloc[common.Address{1}].Storage[common.Hash{2}] = common.Hash{}
+	if genesis.ToBlock().Hash() != block.Hash() {
+		t.Error("zero storage slot changed the genesis hash")
+	}
+	if (&Genesis{}).ToBlock().Root() != types.EmptyRootHash {
+		t.Error("wrong state root of empty alloc")
+	}
+}
+
+// Tests the genesis block of a single account alloc against a state root and
+// header hash assembled by hand from the trie and header encoding rules,
+// independently of the trie hasher and the header type.
+func TestGenesisHandComputed(t *testing.T) {
+	var (
+		emptyRoot     = common.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+		emptyCodeHash = common.HexToHash("0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
+		emptyUncles   = common.HexToHash("0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347")
+	)
+	if root := (GenesisAlloc{}).Root(); root != emptyRoot {
+		t.Errorf("wrong state root of empty alloc %x, want %x", root, emptyRoot)
+	}
+
+	// A trie holding a single key consists of one leaf node whose path is
+	// the full 32 byte key, compact encoded with the even leaf flag 0x20.
+	leafRoot := func(key common.Hash, value []byte) common.Hash {
+		enc, _ := rlp.EncodeToBytes([][]byte{append([]byte{0x20}, key[:]...), value})
+		return crypto.Keccak256Hash(enc)
+	}
+	var (
+		addr        = common.Address{1}
+		slot, value = common.Hash{1}, common.Hash{1}
+	)
+	slotValue, _ := rlp.EncodeToBytes(value[:]) // 0x01 followed by 31 zero bytes, no leading zeros to strip
+	storageRoot := leafRoot(crypto.Keccak256Hash(slot[:]), slotValue)
+	account, _ := rlp.EncodeToBytes([]interface{}{uint64(0), big.NewInt(1), storageRoot, emptyCodeHash})
+	stateRoot := leafRoot(crypto.Keccak256Hash(addr[:]), account)
+
+	genesis := &Genesis{
+		Alloc: GenesisAlloc{addr: {Balance: big.NewInt(1), Storage: map[common.Hash]common.Hash{slot: value}}},
+	}
+	block := genesis.ToBlock()
+	if block.Root() != stateRoot {
+		t.Fatalf("wrong state root %x, want %x", block.Root(), stateRoot)
+	}
+	header, _ := rlp.EncodeToBytes([]interface{}{
+		common.Hash{},      // parent hash
+		emptyUncles,        // uncle hash
+		common.Address{},   // coinbase
+		stateRoot,          // state root
+		emptyRoot,          // transactions root
+		emptyRoot,          // receipts root
+		types.Bloom{},      // bloom
+		big.NewInt(131072), // difficulty
+		uint64(0),          // number
+		uint64(4712388),    // gas limit
+		uint64(0),          // gas used
+		uint64(0),          // timestamp
+		[]byte{},           // extra data
+		common.Hash{},      // mix digest
+		types.BlockNonce{}, // nonce
+	})
+	if want := crypto.Keccak256Hash(header); block.Hash() != want {
+		t.Errorf("wrong genesis hash %x, want %x", block.Hash(), want)
+	}
+}
+
+func TestGenesisBaseFee(t *testing.T) {
+	genesis := &Genesis{Config: &params.ChainConfig{LondonBlock: big.NewInt(0)}}
+	if fee := genesis.ToBlock().BaseFee(); fee == nil || fee.Uint64() != params.InitialBaseFee {
+		t.Errorf("wrong default base fee %v", fee)
+	}
+	genesis.BaseFee = big.NewInt(7)
+	if fee := genesis.ToBlock().BaseFee(); fee == nil || fee.Uint64() != 7 {
+		t.Errorf("wrong base fee %v, want 7", fee)
+	}
+	genesis.Config.LondonBlock = big.NewInt(1)
+	if fee := genesis.ToBlock().BaseFee(); fee != nil {
+		t.Errorf("base fee %v set before London", fee)
+	}
+}
+
+// Tests the genesis block of the Sepolia test network, which has London
+// active at genesis.
+func TestGenesisSepolia(t *testing.T) {
+	input, err := os.ReadFile("testdata/sepolia.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var genesis Genesis
+	if err := json.Unmarshal(input, &genesis); err != nil {
+		t.Fatal(err)
+	}
+	block := genesis.ToBlock()
+	if want := common.HexToHash("0x25a5cc106eea7138acab33231d7160d69cb777ee0c2c553fcddf5138993e6dd9"); block.Hash() != want {
+		t.Errorf("wrong genesis hash %x, want %x", block.Hash(), want)
+	}
+	if fee := block.BaseFee(); fee == nil || fee.Uint64() != params.InitialBaseFee {
+		t.Errorf("wrong base fee %v", fee)
+	}
+}
+
+func TestGenesisJSON(t *testing.T) {
+	input := `{
+		"config": {"chainId": 1337, "homesteadBlock": 0},
+		"nonce": "0x42",
+		"timestamp": "10",
+		"extraData": "0x1234",
+		"gasLimit": "0x47b760",
+		"difficulty": "131072",
+		"baseFeePerGas": "0x7",
+		"alloc": {
+			"0100000000000000000000000000000000000000": {
+				"balance": "0x1",
+				"code": "0x6000",
+				"storage": {"0x01": "0x02"}
+			},
+			"0x0200000000000000000000000000000000000000": {"balance": "1000", "nonce": "0x3"}
+		}
+	}`
+	var genesis Genesis
+	if err := json.Unmarshal([]byte(input), &genesis); err != nil {
+		t.Fatal(err)
+	}
+	want := &Genesis{
+		Config:     &params.ChainConfig{ChainID: big.NewInt(1337), HomesteadBlock: big.NewInt(0)},
+		Nonce:      0x42,
+		Timestamp:  10,
+		ExtraData:  []byte{0x12, 0x34},
+		GasLimit:   4700000,
+		Difficulty: big.NewInt(131072),
+		BaseFee:    big.NewInt(7),
+		Alloc: GenesisAlloc{
+			{1}: {
+				Balance: big.NewInt(1),
+				Code:    []byte{0x60, 0x00},
+				Storage: map[common.Hash]common.Hash{common.BigToHash(big.NewInt(1)): common.BigToHash(big.NewInt(2))},
+			},
+			{2}: {Balance: big.NewInt(1000), Nonce: 3},
+		},
+	}
+	if !reflect.DeepEqual(&genesis, want) {
+		t.Fatalf("wrong genesis\ngot  %+v\nwant %+v", genesis, want)
+	}
+	enc, err := json.Marshal(&genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Genesis
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&dec, want) {
+		t.Errorf("round trip changed the genesis\n%s", enc)
+	}
+	block := dec.ToBlock()
+	if block.Nonce() != 0x42 || block.Time().Uint64() != 10 || string(block.Extra()) != "\x12\x34" || block.GasLimit() != 4700000 {
+		t.Errorf("wrong header %+v", block.Header())
+	}
+	if block.BaseFee() != nil {
+		t.Errorf("base fee %v set before London", block.BaseFee())
+	}
+
+	if err := json.Unmarshal([]byte(`{"difficulty": "1", "alloc": {}}`), new(Genesis)); err == nil {
+		t.Error("missing gas limit accepted")
+	}
+	if err := json.Unmarshal([]byte(`{"gasLimit": "1", "difficulty": "1", "alloc": {"0100000000000000000000000000000000000000": {}}}`), new(Genesis)); err == nil {
+		t.Error("missing balance accepted")
+	}
+}
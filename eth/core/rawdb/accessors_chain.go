@@ -0,0 +1,270 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/ethdb"
+	"github.com/arcology-network/3rd-party/eth/params"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+// DatabaseReader wraps the Has and Get method of a backing data store.
+type DatabaseReader interface {
+	Has(key []byte) (bool, error)
+	Get(key []byte) ([]byte, error)
+}
+
+// ReadCanonicalHash retrieves the hash assigned to a canonical block number.
+func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
+	data, _ := db.Get(headerHashKey(number))
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteCanonicalHash stores the hash assigned to a canonical block number.
+func WriteCanonicalHash(db ethdb.Putter, hash common.Hash, number uint64) error {
+	return db.Put(headerHashKey(number), hash.Bytes())
+}
+
+// DeleteCanonicalHash removes the number to hash canonical mapping, e.g.
+// when a reorg shortens the canonical chain.
+func DeleteCanonicalHash(db ethdb.Deleter, number uint64) error {
+	return db.Delete(headerHashKey(number))
+}
+
+// ReadHeaderNumber returns the header number assigned to a hash.
+func ReadHeaderNumber(db DatabaseReader, hash common.Hash) *uint64 {
+	data, _ := db.Get(headerNumberKey(hash))
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// ReadHeadHeaderHash retrieves the hash of the current canonical head header.
+func ReadHeadHeaderHash(db DatabaseReader) common.Hash {
+	data, _ := db.Get(headHeaderKey)
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteHeadHeaderHash stores the hash of the current canonical head header.
+func WriteHeadHeaderHash(db ethdb.Putter, hash common.Hash) error {
+	return db.Put(headHeaderKey, hash.Bytes())
+}
+
+// ReadHeadBlockHash retrieves the hash of the current canonical head block.
+func ReadHeadBlockHash(db DatabaseReader) common.Hash {
+	data, _ := db.Get(headBlockKey)
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteHeadBlockHash stores the hash of the current canonical head block.
+func WriteHeadBlockHash(db ethdb.Putter, hash common.Hash) error {
+	return db.Put(headBlockKey, hash.Bytes())
+}
+
+// ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
+func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(headerKey(number, hash))
+	return data
+}
+
+// HasHeader verifies the existence of a block header corresponding to the hash.
+func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
+	has, err := db.Has(headerKey(number, hash))
+	return err == nil && has
+}
+
+// ReadHeader retrieves the block header corresponding to the hash.
+func ReadHeader(db DatabaseReader, hash common.Hash, number uint64) *types.Header {
+	data := ReadHeaderRLP(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	header := new(types.Header)
+	if err := rlp.Decode(bytes.NewReader(data), header); err != nil {
+		return nil
+	}
+	return header
+}
+
+// WriteHeader stores a block header into the database and also stores the
+// hash to number mapping.
+func WriteHeader(db ethdb.Putter, header *types.Header) error {
+	hash, number := header.Hash(), header.Number.Uint64()
+	if err := db.Put(headerNumberKey(hash), encodeBlockNumber(number)); err != nil {
+		return err
+	}
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return err
+	}
+	return db.Put(headerKey(number, hash), data)
+}
+
+// DeleteHeader removes all block header data associated with a hash.
+func DeleteHeader(db ethdb.Deleter, hash common.Hash, number uint64) error {
+	if err := db.Delete(headerKey(number, hash)); err != nil {
+		return err
+	}
+	return db.Delete(headerNumberKey(hash))
+}
+
+// ReadBodyRLP retrieves the block body (transactions and uncles) in RLP encoding.
+func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(blockBodyKey(number, hash))
+	return data
+}
+
+// HasBody verifies the existence of a block body corresponding to the hash.
+func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
+	has, err := db.Has(blockBodyKey(number, hash))
+	return err == nil && has
+}
+
+// ReadBody retrieves the block body corresponding to the hash.
+func ReadBody(db DatabaseReader, hash common.Hash, number uint64) *types.Body {
+	data := ReadBodyRLP(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	body := new(types.Body)
+	if err := rlp.Decode(bytes.NewReader(data), body); err != nil {
+		return nil
+	}
+	return body
+}
+
+// WriteBody stores a block body into the database.
+func WriteBody(db ethdb.Putter, hash common.Hash, number uint64, body *types.Body) error {
+	data, err := rlp.EncodeToBytes(body)
+	if err != nil {
+		return err
+	}
+	return db.Put(blockBodyKey(number, hash), data)
+}
+
+// DeleteBody removes all block body data associated with a hash.
+func DeleteBody(db ethdb.Deleter, hash common.Hash, number uint64) error {
+	return db.Delete(blockBodyKey(number, hash))
+}
+
+// ReadRawReceipts retrieves all the transaction receipts belonging to a block.
+// Only the fields stored by types.ReceiptForStorage are set, the fields
+// derived from the block are left empty.
+func ReadRawReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
+	data, _ := db.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var storageReceipts []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(data, &storageReceipts); err != nil {
+		return nil
+	}
+	receipts := make(types.Receipts, len(storageReceipts))
+	for i, receipt := range storageReceipts {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	return receipts
+}
+
+// ReadReceipts retrieves all the transaction receipts belonging to a block,
+// including the fields derived from the block and its transactions. If the
+// receipts or the block body could not be retrieved, or the fields could not
+// be derived, nil is returned.
+func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64, config *params.ChainConfig) types.Receipts {
+	receipts := ReadRawReceipts(db, hash, number)
+	if receipts == nil {
+		return nil
+	}
+	body := ReadBody(db, hash, number)
+	if body == nil {
+		return nil
+	}
+	if err := receipts.DeriveFields(config, hash, number, body.Transactions); err != nil {
+		return nil
+	}
+	return receipts
+}
+
+// WriteReceipts stores all the transaction receipts belonging to a block.
+func WriteReceipts(db ethdb.Putter, hash common.Hash, number uint64, receipts types.Receipts) error {
+	storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+	for i, receipt := range receipts {
+		storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+	}
+	data, err := rlp.EncodeToBytes(storageReceipts)
+	if err != nil {
+		return err
+	}
+	return db.Put(blockReceiptsKey(number, hash), data)
+}
+
+// DeleteReceipts removes all receipt data associated with a block hash.
+func DeleteReceipts(db ethdb.Deleter, hash common.Hash, number uint64) error {
+	return db.Delete(blockReceiptsKey(number, hash))
+}
+
+// ReadBlock retrieves an entire block corresponding to the hash, assembling it
+// back from the stored header and body. If either the header or body could not
+// be retrieved nil is returned.
+func ReadBlock(db DatabaseReader, hash common.Hash, number uint64) *types.Block {
+	header := ReadHeader(db, hash, number)
+	if header == nil {
+		return nil
+	}
+	body := ReadBody(db, hash, number)
+	if body == nil {
+		return nil
+	}
+	return types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles)
+}
+
+// WriteBlock serializes a block into the database, header and body separately.
+func WriteBlock(db ethdb.Putter, block *types.Block) error {
+	if err := WriteBody(db, block.Hash(), block.NumberU64(), block.Body()); err != nil {
+		return err
+	}
+	return WriteHeader(db, block.Header())
+}
+
+// DeleteBlock removes all block data associated with a hash: the header, the
+// body and the receipts. The canonical mapping and the transaction lookup
+// entries are left alone, reorgs remove them separately.
+func DeleteBlock(db ethdb.Deleter, hash common.Hash, number uint64) error {
+	if err := DeleteReceipts(db, hash, number); err != nil {
+		return err
+	}
+	if err := DeleteHeader(db, hash, number); err != nil {
+		return err
+	}
+	return DeleteBody(db, hash, number)
+}
@@ -0,0 +1,253 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/ethdb"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+func testHeader(number int64, extra string) *types.Header {
+	return &types.Header{Number: big.NewInt(number), Difficulty: big.NewInt(1), Time: big.NewInt(number), Extra: []byte(extra)}
+}
+
+// Tests block header storage and retrieval operations.
+func TestHeaderStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	header := testHeader(42, "test header")
+	if entry := ReadHeader(db, header.Hash(), header.Number.Uint64()); entry != nil {
+		t.Fatalf("Non existent header returned: %v", entry)
+	}
+	if err := WriteHeader(db, header); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if entry := ReadHeader(db, header.Hash(), header.Number.Uint64()); entry == nil {
+		t.Fatalf("Stored header not found")
+	} else if entry.Hash() != header.Hash() {
+		t.Fatalf("Retrieved header mismatch: have %v, want %v", entry, header)
+	}
+	if !HasHeader(db, header.Hash(), 42) || HasHeader(db, header.Hash(), 41) {
+		t.Fatalf("Wrong header existence")
+	}
+	if number := ReadHeaderNumber(db, header.Hash()); number == nil || *number != 42 {
+		t.Fatalf("Wrong header number: %v", number)
+	}
+	if err := DeleteHeader(db, header.Hash(), header.Number.Uint64()); err != nil {
+		t.Fatalf("Failed to delete header: %v", err)
+	}
+	if entry := ReadHeader(db, header.Hash(), header.Number.Uint64()); entry != nil {
+		t.Fatalf("Deleted header returned: %v", entry)
+	}
+	if number := ReadHeaderNumber(db, header.Hash()); number != nil {
+		t.Fatalf("Deleted header number returned: %d", *number)
+	}
+}
+
+// Tests block body storage and retrieval operations.
+func TestBodyStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	body := &types.Body{Uncles: []*types.Header{testHeader(1, "test header")}}
+	hash := common.Hash{0x42}
+	if entry := ReadBody(db, hash, 0); entry != nil {
+		t.Fatalf("Non existent body returned: %v", entry)
+	}
+	if err := WriteBody(db, hash, 0, body); err != nil {
+		t.Fatalf("Failed to write body: %v", err)
+	}
+	if entry := ReadBody(db, hash, 0); entry == nil {
+		t.Fatalf("Stored body not found")
+	} else if types.CalcUncleHash(entry.Uncles) != types.CalcUncleHash(body.Uncles) {
+		t.Fatalf("Retrieved body mismatch: have %v, want %v", entry, body)
+	}
+	if enc, _ := rlp.EncodeToBytes(body); !reflect.DeepEqual([]byte(ReadBodyRLP(db, hash, 0)), enc) {
+		t.Fatalf("Retrieved RLP body mismatch")
+	}
+	if err := DeleteBody(db, hash, 0); err != nil {
+		t.Fatalf("Failed to delete body: %v", err)
+	}
+	if HasBody(db, hash, 0) {
+		t.Fatalf("Deleted body still present")
+	}
+}
+
+// Tests block storage and retrieval operations.
+func TestBlockStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	txs := []*types.Transaction{
+		types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		types.NewTransaction(1, common.Address{2}, big.NewInt(2), 21000, big.NewInt(1), []byte{1}),
+	}
+	receipts := []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, GasUsed: 21000, TxHash: txs[0].Hash()},
+		{Status: types.ReceiptStatusFailed, CumulativeGasUsed: 42000, GasUsed: 21000, TxHash: txs[1].Hash()},
+	}
+	block := types.NewBlock(testHeader(7, "test block"), txs, []*types.Header{testHeader(6, "uncle")}, receipts)
+	hash, number := block.Hash(), block.NumberU64()
+
+	if entry := ReadBlock(db, hash, number); entry != nil {
+		t.Fatalf("Non existent block returned: %v", entry)
+	}
+	if err := WriteBlock(db, block); err != nil {
+		t.Fatalf("Failed to write block: %v", err)
+	}
+	entry := ReadBlock(db, hash, number)
+	if entry == nil {
+		t.Fatalf("Stored block not found")
+	}
+	if entry.Hash() != hash || types.DeriveSha(entry.Transactions()) != block.TxHash() || types.CalcUncleHash(entry.Uncles()) != block.UncleHash() {
+		t.Fatalf("Retrieved block mismatch: have %v, want %v", entry, block)
+	}
+	// A block is only readable with both header and body present.
+	if err := DeleteBody(db, hash, number); err != nil {
+		t.Fatal(err)
+	}
+	if entry := ReadBlock(db, hash, number); entry != nil {
+		t.Fatalf("Block without body returned: %v", entry)
+	}
+	if err := WriteBlock(db, block); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteReceipts(db, hash, number, receipts); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteBlock(db, hash, number); err != nil {
+		t.Fatalf("Failed to delete block: %v", err)
+	}
+	if ReadBlock(db, hash, number) != nil || ReadHeader(db, hash, number) != nil || ReadRawReceipts(db, hash, number) != nil {
+		t.Fatalf("Deleted block data returned")
+	}
+}
+
+// Tests that canonical numbers can be mapped to hashes and retrieved.
+func TestCanonicalMappingStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	hash, number := common.Hash{0: 0xff}, uint64(314)
+	if entry := ReadCanonicalHash(db, number); entry != (common.Hash{}) {
+		t.Fatalf("Non existent canonical mapping returned: %v", entry)
+	}
+	if err := WriteCanonicalHash(db, hash, number); err != nil {
+		t.Fatalf("Failed to write canonical mapping: %v", err)
+	}
+	if entry := ReadCanonicalHash(db, number); entry != hash {
+		t.Fatalf("Retrieved canonical mapping mismatch: have %v, want %v", entry, hash)
+	}
+	if err := DeleteCanonicalHash(db, number); err != nil {
+		t.Fatalf("Failed to delete canonical mapping: %v", err)
+	}
+	if entry := ReadCanonicalHash(db, number); entry != (common.Hash{}) {
+		t.Fatalf("Deleted canonical mapping returned: %v", entry)
+	}
+}
+
+// Tests that head headers and head blocks can be assigned, individually.
+func TestHeadStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	blockHead := testHeader(0, "test block header").Hash()
+	blockFull := testHeader(0, "test block full").Hash()
+
+	if entry := ReadHeadHeaderHash(db); entry != (common.Hash{}) {
+		t.Fatalf("Non head header entry returned: %v", entry)
+	}
+	if entry := ReadHeadBlockHash(db); entry != (common.Hash{}) {
+		t.Fatalf("Non head block entry returned: %v", entry)
+	}
+	if err := WriteHeadHeaderHash(db, blockHead); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteHeadBlockHash(db, blockFull); err != nil {
+		t.Fatal(err)
+	}
+	if entry := ReadHeadHeaderHash(db); entry != blockHead {
+		t.Fatalf("Head header hash mismatch: have %v, want %v", entry, blockHead)
+	}
+	if entry := ReadHeadBlockHash(db); entry != blockFull {
+		t.Fatalf("Head block hash mismatch: have %v, want %v", entry, blockFull)
+	}
+}
+
+// Tests that receipts associated with a single block can be stored and retrieved,
+// also through a batch.
+func TestBlockReceiptStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	receipt1 := &types.Receipt{
+		Status:            types.ReceiptStatusFailed,
+		CumulativeGasUsed: 1,
+		Logs: []*types.Log{
+			{Address: common.BytesToAddress([]byte{0x11})},
+			{Address: common.BytesToAddress([]byte{0x01, 0x11})},
+		},
+		TxHash:          common.BytesToHash([]byte{0x11, 0x11}),
+		ContractAddress: common.BytesToAddress([]byte{0x01, 0x11, 0x11}),
+		GasUsed:         111111,
+	}
+	receipt2 := &types.Receipt{
+		PostState:         common.Hash{2}.Bytes(),
+		CumulativeGasUsed: 2,
+		Logs: []*types.Log{
+			{Address: common.BytesToAddress([]byte{0x22})},
+			{Address: common.BytesToAddress([]byte{0x02, 0x22})},
+		},
+		TxHash:          common.BytesToHash([]byte{0x22, 0x22}),
+		ContractAddress: common.BytesToAddress([]byte{0x02, 0x22, 0x22}),
+		GasUsed:         222222,
+	}
+	receipts := []*types.Receipt{receipt1, receipt2}
+
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	if rs := ReadRawReceipts(db, hash, 0); len(rs) != 0 {
+		t.Fatalf("non existent receipts returned: %v", rs)
+	}
+	batch := db.NewBatch()
+	if err := WriteReceipts(batch, hash, 0, receipts); err != nil {
+		t.Fatal(err)
+	}
+	if rs := ReadRawReceipts(db, hash, 0); len(rs) != 0 {
+		t.Fatalf("receipts visible before batch write: %v", rs)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	rs := ReadRawReceipts(db, hash, 0)
+	if len(rs) != len(receipts) {
+		t.Fatalf("receipts returned %d, want %d", len(rs), len(receipts))
+	}
+	for i := range receipts {
+		want, _ := rlp.EncodeToBytes((*types.ReceiptForStorage)(receipts[i]))
+		have, _ := rlp.EncodeToBytes((*types.ReceiptForStorage)(rs[i]))
+		if !reflect.DeepEqual(have, want) {
+			t.Fatalf("receipt #%d: receipt mismatch: have %v, want %v", i, rs[i], receipts[i])
+		}
+	}
+	if err := DeleteReceipts(db, hash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if rs := ReadRawReceipts(db, hash, 0); len(rs) != 0 {
+		t.Fatalf("deleted receipts returned: %v", rs)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/ethdb"
+	"github.com/arcology-network/3rd-party/eth/params"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+// ReadTxLookupEntry retrieves the number of the block including the
+// transaction with the given hash.
+func ReadTxLookupEntry(db DatabaseReader, hash common.Hash) *uint64 {
+	data, _ := db.Get(txLookupKey(hash))
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteTxLookupEntries stores a positional metadata for every transaction
+// from a block, enabling hash based transaction and receipt lookups.
+func WriteTxLookupEntries(db ethdb.Putter, block *types.Block) error {
+	number := encodeBlockNumber(block.NumberU64())
+	for _, tx := range block.Transactions() {
+		if err := db.Put(txLookupKey(tx.Hash()), number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTxLookupEntry removes all transaction data associated with a hash.
+func DeleteTxLookupEntry(db ethdb.Deleter, hash common.Hash) error {
+	return db.Delete(txLookupKey(hash))
+}
+
+// DeleteTxLookupEntries removes the lookup entries of all transactions of
+// a block, e.g. when a reorg drops the block from the canonical chain.
+func DeleteTxLookupEntries(db ethdb.Deleter, block *types.Block) error {
+	for _, tx := range block.Transactions() {
+		if err := DeleteTxLookupEntry(db, tx.Hash()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadTransaction retrieves a specific transaction from the database, along with
+// its added positional metadata. The transaction is looked up in the canonical
+// block at the number stored in its lookup entry.
+func ReadTransaction(db DatabaseReader, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64) {
+	number := ReadTxLookupEntry(db, hash)
+	if number == nil {
+		return nil, common.Hash{}, 0, 0
+	}
+	blockHash := ReadCanonicalHash(db, *number)
+	if blockHash == (common.Hash{}) {
+		return nil, common.Hash{}, 0, 0
+	}
+	body := ReadBody(db, blockHash, *number)
+	if body == nil {
+		return nil, common.Hash{}, 0, 0
+	}
+	for index, tx := range body.Transactions {
+		if tx.Hash() == hash {
+			return tx, blockHash, *number, uint64(index)
+		}
+	}
+	return nil, common.Hash{}, 0, 0
+}
+
+// ReadReceipt retrieves a specific transaction receipt from the database, along with
+// its added positional metadata.
+func ReadReceipt(db DatabaseReader, hash common.Hash, config *params.ChainConfig) (*types.Receipt, common.Hash, uint64, uint64) {
+	number := ReadTxLookupEntry(db, hash)
+	if number == nil {
+		return nil, common.Hash{}, 0, 0
+	}
+	blockHash := ReadCanonicalHash(db, *number)
+	if blockHash == (common.Hash{}) {
+		return nil, common.Hash{}, 0, 0
+	}
+	receipts := ReadReceipts(db, blockHash, *number, config)
+	for index, receipt := range receipts {
+		if receipt.TxHash == hash {
+			return receipt, blockHash, *number, uint64(index)
+		}
+	}
+	return nil, common.Hash{}, 0, 0
+}
+
+// ReadBloomBits retrieves the bit vector of a bloom bit in a stored section
+// of the bloom bits index.
+func ReadBloomBits(db DatabaseReader, bit uint, section uint64) ([]byte, error) {
+	return db.Get(bloomBitsKey(bit, section))
+}
+
+// WriteBloomBits stores the bit vector of a bloom bit in a section of the
+// bloom bits index.
+func WriteBloomBits(db ethdb.Putter, bit uint, section uint64, bits []byte) error {
+	return db.Put(bloomBitsKey(bit, section), bits)
+}
+
+// ReadBloomSections retrieves the section size and the number of stored
+// sections of the bloom bits index. Both are zero if no index is stored.
+func ReadBloomSections(db DatabaseReader) (sectionSize uint64, sections uint64) {
+	data, _ := db.Get(bloomSectionSizeKey)
+	if len(data) != 8 {
+		return 0, 0
+	}
+	sectionSize = binary.BigEndian.Uint64(data)
+	if data, _ = db.Get(bloomSectionsKey); len(data) == 8 {
+		sections = binary.BigEndian.Uint64(data)
+	}
+	return sectionSize, sections
+}
+
+// WriteBloomSections stores the section size and the number of stored
+// sections of the bloom bits index.
+func WriteBloomSections(db ethdb.Putter, sectionSize uint64, sections uint64) error {
+	if err := db.Put(bloomSectionSizeKey, encodeBlockNumber(sectionSize)); err != nil {
+		return err
+	}
+	return db.Put(bloomSectionsKey, encodeBlockNumber(sections))
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/ethdb"
+	"github.com/arcology-network/3rd-party/eth/params"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+// Tests that positional lookup metadata can be stored and retrieved, and
+// that reorgs can remove it.
+func TestLookupStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	tx1 := types.NewTransaction(1, common.BytesToAddress([]byte{0x11}), big.NewInt(111), 1111, big.NewInt(11111), []byte{0x11, 0x11, 0x11})
+	tx2 := types.NewTransaction(2, common.BytesToAddress([]byte{0x22}), big.NewInt(222), 2222, big.NewInt(22222), []byte{0x22, 0x22, 0x22})
+	tx3 := types.NewTransaction(3, common.BytesToAddress([]byte{0x33}), big.NewInt(333), 3333, big.NewInt(33333), []byte{0x33, 0x33, 0x33})
+	txs := []*types.Transaction{tx1, tx2, tx3}
+
+	block := types.NewBlock(testHeader(314, ""), txs, nil, nil)
+
+	// Check that no transactions entries are in a pristine database
+	for i, tx := range txs {
+		if txn, _, _, _ := ReadTransaction(db, tx.Hash()); txn != nil {
+			t.Fatalf("tx #%d [%x]: non existent transaction returned: %v", i, tx.Hash(), txn)
+		}
+	}
+	// Insert all the transactions into the database, and verify contents
+	if err := WriteCanonicalHash(db, block.Hash(), block.NumberU64()); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteBlock(db, block); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteTxLookupEntries(db, block); err != nil {
+		t.Fatal(err)
+	}
+	for i, tx := range txs {
+		txn, hash, number, index := ReadTransaction(db, tx.Hash())
+		if txn == nil {
+			t.Fatalf("tx #%d [%x]: transaction not found", i, tx.Hash())
+		}
+		if hash != block.Hash() || number != block.NumberU64() || index != uint64(i) {
+			t.Fatalf("tx #%d [%x]: positional metadata mismatch: have %x/%d/%d, want %x/%v/%v", i, tx.Hash(), hash, number, index, block.Hash(), block.NumberU64(), i)
+		}
+		if tx.Hash() != txn.Hash() {
+			t.Fatalf("tx #%d [%x]: transaction mismatch: have %v, want %v", i, tx.Hash(), txn, tx)
+		}
+	}
+	// A block that is no longer canonical does not serve its transactions.
+	if err := DeleteCanonicalHash(db, block.NumberU64()); err != nil {
+		t.Fatal(err)
+	}
+	if txn, _, _, _ := ReadTransaction(db, tx1.Hash()); txn != nil {
+		t.Fatalf("transaction of non-canonical block returned: %v", txn)
+	}
+	// Delete the transactions and check purge
+	if err := DeleteTxLookupEntries(db, block); err != nil {
+		t.Fatal(err)
+	}
+	for i, tx := range txs {
+		if number := ReadTxLookupEntry(db, tx.Hash()); number != nil {
+			t.Fatalf("tx #%d [%x]: deleted lookup entry returned: %d", i, tx.Hash(), *number)
+		}
+	}
+}
+
+// Tests that stored receipts are returned with the fields derived from their
+// block, both per block and per transaction.
+func TestReceiptLookup(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	txs := []*types.Transaction{
+		types.NewTransaction(1, common.Address{0x11}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		types.NewTransaction(2, common.Address{0x22}, big.NewInt(2), 50000, big.NewInt(1), nil),
+	}
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{{Address: common.Address{1}}}},
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 64000, Logs: []*types.Log{{Address: common.Address{2}}, {Address: common.Address{3}}}},
+	}
+	block := types.NewBlock(testHeader(5, ""), txs, nil, receipts)
+	hash, number := block.Hash(), block.NumberU64()
+
+	if err := WriteBlock(db, block); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteReceipts(db, hash, number, receipts); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteCanonicalHash(db, hash, number); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteTxLookupEntries(db, block); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := ReadReceipts(db, hash, number, params.TestChainConfig)
+	if len(rs) != 2 {
+		t.Fatalf("got %d receipts, want 2", len(rs))
+	}
+	if rs[1].GasUsed != 43000 || rs[1].TxHash != txs[1].Hash() || rs[1].BlockHash != hash || rs[1].TransactionIndex != 1 {
+		t.Errorf("wrong derived receipt fields %+v", rs[1])
+	}
+	if log := rs[1].Logs[1]; log.Index != 2 || log.TxIndex != 1 || log.BlockNumber != number {
+		t.Errorf("wrong derived log fields %+v", log)
+	}
+	if types.DeriveSha(rs) != block.ReceiptHash() {
+		t.Error("derived receipts do not match the receipt root")
+	}
+
+	receipt, blockHash, blockNumber, index := ReadReceipt(db, txs[0].Hash(), params.TestChainConfig)
+	if receipt == nil || receipt.TxHash != txs[0].Hash() || receipt.GasUsed != 21000 {
+		t.Fatalf("wrong receipt %+v", receipt)
+	}
+	if blockHash != hash || blockNumber != number || index != 0 {
+		t.Errorf("wrong positional metadata %x/%d/%d", blockHash, blockNumber, index)
+	}
+
+	// Receipts cannot be derived without the block body.
+	if err := DeleteBody(db, hash, number); err != nil {
+		t.Fatal(err)
+	}
+	if rs := ReadReceipts(db, hash, number, params.TestChainConfig); rs != nil {
+		t.Errorf("receipts without body returned: %v", rs)
+	}
+	if rs := ReadRawReceipts(db, hash, number); len(rs) != 2 {
+		t.Errorf("got %d raw receipts, want 2", len(rs))
+	}
+}
+
+// Tests that bloom bits vectors and the index metadata can be stored and
+// retrieved.
+func TestBloomBitsStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	if size, sections := ReadBloomSections(db); size != 0 || sections != 0 {
+		t.Fatalf("non existent index returned: size %d, sections %d", size, sections)
+	}
+	if bits, err := ReadBloomBits(db, 7, 0); err == nil {
+		t.Fatalf("non existent bit vector returned: %x", bits)
+	}
+	vector := []byte{0x80, 0x01}
+	if err := WriteBloomBits(db, 7, 3, vector); err != nil {
+		t.Fatalf("failed to write bit vector: %v", err)
+	}
+	if err := WriteBloomSections(db, 16, 4); err != nil {
+		t.Fatalf("failed to write index metadata: %v", err)
+	}
+	if bits, err := ReadBloomBits(db, 7, 3); err != nil || !bytes.Equal(bits, vector) {
+		t.Fatalf("bit vector mismatch: have %x (%v), want %x", bits, err, vector)
+	}
+	if bits, err := ReadBloomBits(db, 8, 3); err == nil {
+		t.Fatalf("bit vector of another bit returned: %x", bits)
+	}
+	if size, sections := ReadBloomSections(db); size != 16 || sections != 4 {
+		t.Fatalf("index metadata mismatch: have size %d, sections %d, want 16, 4", size, sections)
+	}
+}
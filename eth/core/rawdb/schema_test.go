@@ -0,0 +1,47 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Tests that no database key or key prefix is a prefix of another one, so
+// entries of different kinds can never collide or be confused when
+// iterating over a prefix.
+func TestSchemaPrefixFree(t *testing.T) {
+	keys := map[string][]byte{
+		"headHeaderKey":       headHeaderKey,
+		"headBlockKey":        headBlockKey,
+		"headerPrefix":        headerPrefix,
+		"headerNumberPrefix":  headerNumberPrefix,
+		"blockBodyPrefix":     blockBodyPrefix,
+		"blockReceiptsPrefix": blockReceiptsPrefix,
+		"txLookupPrefix":      txLookupPrefix,
+		"bloomBitsPrefix":     bloomBitsPrefix,
+		"bloomSectionsKey":    bloomSectionsKey,
+		"bloomSectionSizeKey": bloomSectionSizeKey,
+	}
+	for name1, key1 := range keys {
+		for name2, key2 := range keys {
+			if name1 != name2 && bytes.HasPrefix(key2, key1) {
+				t.Errorf("%s %q is a prefix of %s %q", name1, key1, name2, key2)
+			}
+		}
+	}
+}
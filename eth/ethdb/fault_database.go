@@ -0,0 +1,224 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethdb
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrInjected is the default error returned by an operation hit by a Fault.
+var ErrInjected = errors.New("ethdb: injected fault")
+
+// Op identifies a database operation a Fault applies to.
+type Op uint8
+
+const (
+	OpPut Op = 1 << iota
+	OpGet
+	OpDelete
+	OpBatchWrite
+
+	OpAll = OpPut | OpGet | OpDelete | OpBatchWrite
+)
+
+// Fault describes an error and/or delay injected into matching operations.
+// A batch write matches if any key written by the batch matches.
+type Fault struct {
+	Ops         Op            // operations the fault applies to, zero means OpAll
+	Prefix      []byte        // only keys with this prefix match, nil matches all keys
+	Probability float64       // chance a matching operation is hit, zero means always
+	Skip        int           // number of matching operations to let through first
+	Count       int           // number of operations to hit before the fault is spent, zero means unlimited
+	Latency     time.Duration // delay added to every hit operation
+	Err         error         // error returned by hit operations, nil means ErrInjected
+	NoError     bool          // only inject latency, let the operation succeed
+
+	hits int
+}
+
+// FaultDatabase is a Database wrapper for testing that injects configurable
+// errors and latency into Put, Get, Delete and Batch.Write.
+type FaultDatabase struct {
+	db     Database
+	faults []*Fault
+	rand   *rand.Rand
+	lock   sync.Mutex
+}
+
+// NewFaultDatabase returns a FaultDatabase wrapping db. No faults are
+// injected until added with AddFault.
+func NewFaultDatabase(db Database) *FaultDatabase {
+	return &FaultDatabase{
+		db:   db,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Seed resets the source used for probabilistic faults, making them
+// reproducible.
+func (db *FaultDatabase) Seed(seed int64) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.rand = rand.New(rand.NewSource(seed))
+}
+
+// AddFault registers a fault. Faults are checked in the order they were added
+// and the first one hit determines the outcome of the operation.
+func (db *FaultDatabase) AddFault(f Fault) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	f.Prefix = append([]byte(nil), f.Prefix...)
+	f.hits = 0
+	db.faults = append(db.faults, &f)
+}
+
+// ClearFaults removes all registered faults.
+func (db *FaultDatabase) ClearFaults() {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.faults = nil
+}
+
+// inject evaluates the registered faults for an operation on the given keys,
+// sleeping for the configured latency and returning the error to fail with.
+func (db *FaultDatabase) inject(op Op, keys ...[]byte) error {
+	db.lock.Lock()
+	var hit *Fault
+	for _, f := range db.faults {
+		if f.match(op, keys) && f.fire(db.rand) {
+			hit = f
+			break
+		}
+	}
+	db.lock.Unlock()
+
+	if hit == nil {
+		return nil
+	}
+	if hit.Latency > 0 {
+		time.Sleep(hit.Latency)
+	}
+	if hit.NoError {
+		return nil
+	}
+	if hit.Err != nil {
+		return hit.Err
+	}
+	return ErrInjected
+}
+
+func (f *Fault) match(op Op, keys [][]byte) bool {
+	if f.Ops != 0 && f.Ops&op == 0 {
+		return false
+	}
+	if f.Count > 0 && f.hits >= f.Skip+f.Count {
+		return false
+	}
+	if len(keys) == 0 {
+		return len(f.Prefix) == 0
+	}
+	for _, key := range keys {
+		if bytes.HasPrefix(key, f.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fire reports whether a matching operation is hit. The caller must hold the
+// database lock.
+func (f *Fault) fire(r *rand.Rand) bool {
+	if f.Probability > 0 && r.Float64() >= f.Probability {
+		return false
+	}
+	f.hits++
+	return f.hits > f.Skip
+}
+
+func (db *FaultDatabase) Put(key []byte, value []byte) error {
+	if err := db.inject(OpPut, key); err != nil {
+		return err
+	}
+	return db.db.Put(key, value)
+}
+
+func (db *FaultDatabase) Has(key []byte) (bool, error) {
+	return db.db.Has(key)
+}
+
+func (db *FaultDatabase) Get(key []byte) ([]byte, error) {
+	if err := db.inject(OpGet, key); err != nil {
+		return nil, err
+	}
+	return db.db.Get(key)
+}
+
+func (db *FaultDatabase) Delete(key []byte) error {
+	if err := db.inject(OpDelete, key); err != nil {
+		return err
+	}
+	return db.db.Delete(key)
+}
+
+func (db *FaultDatabase) Close() {
+	db.db.Close()
+}
+
+func (db *FaultDatabase) NewBatch() Batch {
+	return &faultBatch{db: db, batch: db.db.NewBatch()}
+}
+
+type faultBatch struct {
+	db    *FaultDatabase
+	batch Batch
+	keys  [][]byte
+}
+
+func (b *faultBatch) Put(key, value []byte) error {
+	b.keys = append(b.keys, append([]byte(nil), key...))
+	return b.batch.Put(key, value)
+}
+
+func (b *faultBatch) Delete(key []byte) error {
+	b.keys = append(b.keys, append([]byte(nil), key...))
+	return b.batch.Delete(key)
+}
+
+// Write commits the batch unless a fault is hit, in which case nothing is
+// written to the underlying database.
+func (b *faultBatch) Write() error {
+	if err := b.db.inject(OpBatchWrite, b.keys...); err != nil {
+		return err
+	}
+	return b.batch.Write()
+}
+
+func (b *faultBatch) ValueSize() int {
+	return b.batch.ValueSize()
+}
+
+func (b *faultBatch) Reset() {
+	b.batch.Reset()
+	b.keys = b.keys[:0]
+}
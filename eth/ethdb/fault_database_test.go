@@ -0,0 +1,132 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFaultDB_PutGet(t *testing.T) {
+	testPutGet(NewFaultDatabase(NewMemDatabase()), t)
+}
+
+func TestFaultDB_Prefix(t *testing.T) {
+	db := NewFaultDatabase(NewMemDatabase())
+	db.AddFault(Fault{Ops: OpPut | OpDelete, Prefix: []byte("h")})
+
+	if err := db.Put([]byte("header"), nil); err != ErrInjected {
+		t.Fatalf("put with matching key: got %v, want %v", err, ErrInjected)
+	}
+	if err := db.Delete([]byte("header")); err != ErrInjected {
+		t.Fatalf("delete with matching key: got %v, want %v", err, ErrInjected)
+	}
+	if err := db.Put([]byte("body"), nil); err != nil {
+		t.Fatalf("put with other key failed: %v", err)
+	}
+	if _, err := db.Get([]byte("body")); err != nil {
+		t.Fatalf("get not covered by fault failed: %v", err)
+	}
+	db.ClearFaults()
+	if err := db.Put([]byte("header"), nil); err != nil {
+		t.Fatalf("put after clearing faults failed: %v", err)
+	}
+}
+
+func TestFaultDB_Count(t *testing.T) {
+	errFull := errors.New("disk full")
+	db := NewFaultDatabase(NewMemDatabase())
+	db.AddFault(Fault{Ops: OpPut, Skip: 2, Count: 2, Err: errFull})
+
+	var failed []int
+	for i := 0; i < 6; i++ {
+		if err := db.Put([]byte{byte(i)}, nil); err != nil {
+			if err != errFull {
+				t.Fatalf("put %d: got %v, want %v", i, err, errFull)
+			}
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) != 2 || failed[0] != 2 || failed[1] != 3 {
+		t.Fatalf("wrong operations failed: %v", failed)
+	}
+}
+
+func TestFaultDB_Probability(t *testing.T) {
+	db := NewFaultDatabase(NewMemDatabase())
+	db.Seed(1)
+	db.AddFault(Fault{Ops: OpGet, Probability: 0.5})
+	db.Put([]byte("k"), nil)
+
+	failed := 0
+	for i := 0; i < 1000; i++ {
+		if _, err := db.Get([]byte("k")); err != nil {
+			failed++
+		}
+	}
+	if failed < 400 || failed > 600 {
+		t.Fatalf("unexpected number of failures: %d out of 1000", failed)
+	}
+}
+
+func TestFaultDB_BatchWrite(t *testing.T) {
+	mem := NewMemDatabase()
+	db := NewFaultDatabase(mem)
+	db.AddFault(Fault{Ops: OpBatchWrite, Prefix: []byte("b"), Count: 1})
+
+	batch := db.NewBatch()
+	batch.Put([]byte("a"), []byte("1"))
+	batch.Put([]byte("b"), []byte("2"))
+	if err := batch.Write(); err != ErrInjected {
+		t.Fatalf("batch write: got %v, want %v", err, ErrInjected)
+	}
+	if mem.Len() != 0 {
+		t.Fatalf("failed batch write modified the database")
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("batch write after fault was spent failed: %v", err)
+	}
+	if mem.Len() != 2 {
+		t.Fatalf("batch not written: %d entries", mem.Len())
+	}
+}
+
+func TestReadOnlyDB(t *testing.T) {
+	mem := NewMemDatabase()
+	mem.Put([]byte("k"), []byte("v"))
+	db := NewReadOnlyDatabase(mem)
+
+	if v, err := db.Get([]byte("k")); err != nil || string(v) != "v" {
+		t.Fatalf("get failed: %q, %v", v, err)
+	}
+	if err := db.Put([]byte("k"), nil); err != ErrReadOnly {
+		t.Fatalf("put: got %v, want %v", err, ErrReadOnly)
+	}
+	if err := db.Delete([]byte("k")); err != ErrReadOnly {
+		t.Fatalf("delete: got %v, want %v", err, ErrReadOnly)
+	}
+	batch := db.NewBatch()
+	if err := batch.Put([]byte("x"), nil); err != ErrReadOnly {
+		t.Fatalf("batch put: got %v, want %v", err, ErrReadOnly)
+	}
+	if err := batch.Write(); err != ErrReadOnly {
+		t.Fatalf("batch write: got %v, want %v", err, ErrReadOnly)
+	}
+	if mem.Len() != 1 {
+		t.Fatalf("read-only database modified the underlying database")
+	}
+}
@@ -17,18 +17,23 @@
 package ethdb
 
 import (
+	"bytes"
 	"errors"
+	"sort"
 	"sync"
 
 	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
 
 /*
  * This is a test memory database. Do not use for any production it does not get persisted
  */
 type MemDatabase struct {
-	db   map[string][]byte
-	lock sync.RWMutex
+	db     map[string][]byte
+	size   int  // total number of key and value bytes held
+	shared bool // db is referenced by a copy or snapshot, clone before writing
+	lock   sync.RWMutex
 }
 
 func NewMemDatabase() *MemDatabase {
@@ -47,7 +52,7 @@ func (db *MemDatabase) Put(key []byte, value []byte) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
-	db.db[string(key)] = common.CopyBytes(value)
+	db.put(string(key), common.CopyBytes(value))
 	return nil
 }
 
@@ -84,7 +89,7 @@ func (db *MemDatabase) Delete(key []byte) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
-	delete(db.db, string(key))
+	db.delete(string(key))
 	return nil
 }
 
@@ -94,7 +99,172 @@ func (db *MemDatabase) NewBatch() Batch {
 	return &memBatch{db: db}
 }
 
-func (db *MemDatabase) Len() int { return len(db.db) }
+func (db *MemDatabase) Len() int {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return len(db.db)
+}
+
+// Size returns the number of key and value bytes stored in the database.
+func (db *MemDatabase) Size() int {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.size
+}
+
+// Copy returns an independent, writable copy of the database. The content is
+// shared until either side is modified, so copying is cheap regardless of the
+// database size.
+func (db *MemDatabase) Copy() *MemDatabase {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.shared = true
+	return &MemDatabase{
+		db:     db.db,
+		size:   db.size,
+		shared: true,
+	}
+}
+
+// Snapshot returns a read-only view of the current database content. Later
+// writes to the database are not visible through the snapshot.
+func (db *MemDatabase) Snapshot() *MemSnapshot {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.shared = true
+	return &MemSnapshot{db: db.db, size: db.size}
+}
+
+// NewIterator returns an iterator over the whole database content in
+// ascending key order.
+func (db *MemDatabase) NewIterator() iterator.Iterator {
+	return db.NewIteratorWithPrefix(nil)
+}
+
+// NewIteratorWithPrefix returns an iterator over the subset of database content
+// with a particular prefix, in ascending key order. The iterator works on a
+// snapshot of the database taken when it is created.
+func (db *MemDatabase) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return newMemIterator(db.db, prefix)
+}
+
+// put stores the value and keeps the size accounting up to date. The caller
+// must hold the write lock.
+func (db *MemDatabase) put(key string, value []byte) {
+	db.unshare()
+	if old, ok := db.db[key]; ok {
+		db.size -= len(key) + len(old)
+	}
+	db.db[key] = value
+	db.size += len(key) + len(value)
+}
+
+// delete removes the key and keeps the size accounting up to date. The caller
+// must hold the write lock.
+func (db *MemDatabase) delete(key string) {
+	old, ok := db.db[key]
+	if !ok {
+		return
+	}
+	db.unshare()
+	delete(db.db, key)
+	db.size -= len(key) + len(old)
+}
+
+// unshare clones the underlying map if it is referenced by a copy or snapshot.
+// Values are never mutated in place, so only the map itself needs copying.
+func (db *MemDatabase) unshare() {
+	if !db.shared {
+		return
+	}
+	m := make(map[string][]byte, len(db.db))
+	for k, v := range db.db {
+		m[k] = v
+	}
+	db.db = m
+	db.shared = false
+}
+
+// MemSnapshot is a read-only, point-in-time view of a MemDatabase.
+type MemSnapshot struct {
+	db   map[string][]byte
+	size int
+}
+
+func (snap *MemSnapshot) Has(key []byte) (bool, error) {
+	_, ok := snap.db[string(key)]
+	return ok, nil
+}
+
+func (snap *MemSnapshot) Get(key []byte) ([]byte, error) {
+	if entry, ok := snap.db[string(key)]; ok {
+		return common.CopyBytes(entry), nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (snap *MemSnapshot) Len() int { return len(snap.db) }
+
+// Size returns the number of key and value bytes held by the snapshot.
+func (snap *MemSnapshot) Size() int { return snap.size }
+
+// NewIterator returns an iterator over the snapshot in ascending key order.
+func (snap *MemSnapshot) NewIterator() iterator.Iterator {
+	return newMemIterator(snap.db, nil)
+}
+
+// NewIteratorWithPrefix returns an iterator over the subset of the snapshot
+// with a particular prefix, in ascending key order.
+func (snap *MemSnapshot) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
+	return newMemIterator(snap.db, prefix)
+}
+
+// Copy returns a writable database initialised with the snapshot content.
+func (snap *MemSnapshot) Copy() *MemDatabase {
+	return &MemDatabase{
+		db:     snap.db,
+		size:   snap.size,
+		shared: true,
+	}
+}
+
+// memArray is a sorted key/value list implementing iterator.Array.
+type memArray struct {
+	keys   []string
+	values [][]byte
+}
+
+func newMemIterator(db map[string][]byte, prefix []byte) iterator.Iterator {
+	arr := &memArray{}
+	for key := range db {
+		if bytes.HasPrefix([]byte(key), prefix) {
+			arr.keys = append(arr.keys, key)
+		}
+	}
+	sort.Strings(arr.keys)
+	arr.values = make([][]byte, len(arr.keys))
+	for i, key := range arr.keys {
+		arr.values[i] = db[key]
+	}
+	return iterator.NewArrayIterator(arr)
+}
+
+func (a *memArray) Len() int { return len(a.keys) }
+
+func (a *memArray) Search(key []byte) int {
+	return sort.SearchStrings(a.keys, string(key))
+}
+
+func (a *memArray) Index(i int) (key, value []byte) {
+	return []byte(a.keys[i]), common.CopyBytes(a.values[i])
+}
 
 type kv struct {
 	k, v []byte
@@ -125,10 +295,10 @@ func (b *memBatch) Write() error {
 
 	for _, kv := range b.writes {
 		if kv.del {
-			delete(b.db.db, string(kv.k))
+			b.db.delete(string(kv.k))
 			continue
 		}
-		b.db.db[string(kv.k)] = kv.v
+		b.db.put(string(kv.k), kv.v)
 	}
 	return nil
 }
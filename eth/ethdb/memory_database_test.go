@@ -0,0 +1,124 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethdb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMemoryDB_Copy(t *testing.T) {
+	db := NewMemDatabase()
+	db.Put([]byte("a"), []byte("1"))
+	db.Put([]byte("b"), []byte("2"))
+
+	cpy := db.Copy()
+	cpy.Put([]byte("a"), []byte("changed"))
+	cpy.Delete([]byte("b"))
+	db.Put([]byte("c"), []byte("3"))
+
+	if v, _ := db.Get([]byte("a")); !bytes.Equal(v, []byte("1")) {
+		t.Fatalf("original modified by copy: got %q", v)
+	}
+	if ok, _ := db.Has([]byte("b")); !ok {
+		t.Fatalf("delete in copy leaked into original")
+	}
+	if ok, _ := cpy.Has([]byte("c")); ok {
+		t.Fatalf("write in original leaked into copy")
+	}
+	if v, _ := cpy.Get([]byte("a")); !bytes.Equal(v, []byte("changed")) {
+		t.Fatalf("copy value mismatch: got %q", v)
+	}
+}
+
+func TestMemoryDB_Snapshot(t *testing.T) {
+	db := NewMemDatabase()
+	db.Put([]byte("a"), []byte("1"))
+
+	snap := db.Snapshot()
+	db.Put([]byte("a"), []byte("2"))
+	batch := db.NewBatch()
+	batch.Put([]byte("b"), []byte("3"))
+	batch.Write()
+
+	if v, _ := snap.Get([]byte("a")); !bytes.Equal(v, []byte("1")) {
+		t.Fatalf("snapshot changed after write: got %q", v)
+	}
+	if ok, _ := snap.Has([]byte("b")); ok {
+		t.Fatalf("batch write visible in snapshot")
+	}
+	if snap.Len() != 1 || db.Len() != 2 {
+		t.Fatalf("length mismatch: snapshot %d, db %d", snap.Len(), db.Len())
+	}
+	fork := snap.Copy()
+	fork.Put([]byte("c"), []byte("4"))
+	if ok, _ := snap.Has([]byte("c")); ok {
+		t.Fatalf("write to snapshot copy visible in snapshot")
+	}
+}
+
+func TestMemoryDB_Iterator(t *testing.T) {
+	db := NewMemDatabase()
+	for _, k := range []string{"b2", "a", "b1", "c", "b"} {
+		db.Put([]byte(k), []byte("v"+k))
+	}
+	tests := []struct {
+		prefix string
+		keys   []string
+	}{
+		{"", []string{"a", "b", "b1", "b2", "c"}},
+		{"b", []string{"b", "b1", "b2"}},
+		{"d", nil},
+	}
+	for _, tt := range tests {
+		it := db.NewIteratorWithPrefix([]byte(tt.prefix))
+		var keys []string
+		for it.Next() {
+			if !bytes.Equal(it.Value(), []byte("v"+string(it.Key()))) {
+				t.Errorf("prefix %q: wrong value %q for key %q", tt.prefix, it.Value(), it.Key())
+			}
+			keys = append(keys, string(it.Key()))
+		}
+		it.Release()
+		if len(keys) != len(tt.keys) {
+			t.Fatalf("prefix %q: got keys %q, want %q", tt.prefix, keys, tt.keys)
+		}
+		for i := range keys {
+			if keys[i] != tt.keys[i] {
+				t.Fatalf("prefix %q: got keys %q, want %q", tt.prefix, keys, tt.keys)
+			}
+		}
+	}
+}
+
+func TestMemoryDB_Size(t *testing.T) {
+	db := NewMemDatabase()
+	db.Put([]byte("ab"), []byte("123"))
+	db.Put([]byte("c"), []byte("4"))
+	if size := db.Size(); size != 7 {
+		t.Fatalf("size mismatch: got %d, want 7", size)
+	}
+	db.Put([]byte("ab"), []byte("1"))
+	if size := db.Size(); size != 5 {
+		t.Fatalf("size after overwrite mismatch: got %d, want 5", size)
+	}
+	db.Delete([]byte("c"))
+	db.Delete([]byte("missing"))
+	if size := db.Size(); size != 3 {
+		t.Fatalf("size after delete mismatch: got %d, want 3", size)
+	}
+}
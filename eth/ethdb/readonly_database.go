@@ -0,0 +1,64 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethdb
+
+import "errors"
+
+// ErrReadOnly is returned by write operations on a read-only database.
+var ErrReadOnly = errors.New("ethdb: read-only database")
+
+type readOnlyDatabase struct {
+	db Database
+}
+
+// NewReadOnlyDatabase returns a Database object that serves reads from the
+// given database and rejects all writes with ErrReadOnly.
+func NewReadOnlyDatabase(db Database) Database {
+	return &readOnlyDatabase{db: db}
+}
+
+func (ro *readOnlyDatabase) Put(key []byte, value []byte) error {
+	return ErrReadOnly
+}
+
+func (ro *readOnlyDatabase) Has(key []byte) (bool, error) {
+	return ro.db.Has(key)
+}
+
+func (ro *readOnlyDatabase) Get(key []byte) ([]byte, error) {
+	return ro.db.Get(key)
+}
+
+func (ro *readOnlyDatabase) Delete(key []byte) error {
+	return ErrReadOnly
+}
+
+func (ro *readOnlyDatabase) Close() {
+	// Do nothing; don't close the underlying DB.
+}
+
+func (ro *readOnlyDatabase) NewBatch() Batch {
+	return &readOnlyBatch{}
+}
+
+type readOnlyBatch struct{}
+
+func (b *readOnlyBatch) Put(key, value []byte) error { return ErrReadOnly }
+func (b *readOnlyBatch) Delete(key []byte) error     { return ErrReadOnly }
+func (b *readOnlyBatch) Write() error                { return ErrReadOnly }
+func (b *readOnlyBatch) ValueSize() int              { return 0 }
+func (b *readOnlyBatch) Reset()                      {}
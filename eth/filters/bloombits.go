@@ -0,0 +1,215 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/arcology-network/3rd-party/eth/core/rawdb"
+	"github.com/arcology-network/3rd-party/eth/ethdb"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+// DefaultSectionSize is the number of blocks in a section of the bloom index.
+const DefaultSectionSize = 4096
+
+var (
+	errSectionSize    = errors.New("section size must be a positive multiple of 8")
+	errUnexpectedHead = errors.New("unexpected header number")
+	errVectorLength   = errors.New("invalid bloom bit vector length")
+)
+
+// BloomIndex is a bloombits style index over header blooms. Headers are
+// grouped into sections of consecutive blocks and for every section the
+// index stores 2048 bit vectors, one per bloom bit, telling which blocks of
+// the section have that bit set. A range scan then only has to load the
+// vectors of the bits a filter is interested in, instead of every header.
+//
+// Headers are added in order with Add. Once a section is complete it is
+// written to the database; the partially filled last section only lives in
+// memory and must be scanned by header. When a reorg replaces indexed
+// headers, Rollback discards their bits so the new ones can be added.
+type BloomIndex struct {
+	db          ethdb.Database
+	sectionSize uint64
+	sections    uint64 // number of sections stored in the database
+
+	next uint64   // number of the next header to add
+	bits [][]byte // bit vectors of the section being generated
+}
+
+// NewBloomIndex opens the bloom index stored in db. If the database holds no
+// index yet, a new one with the given section size is started at block 0.
+func NewBloomIndex(db ethdb.Database, sectionSize uint64) (*BloomIndex, error) {
+	if sectionSize == 0 || sectionSize%8 != 0 {
+		return nil, errSectionSize
+	}
+	idx := &BloomIndex{db: db, sectionSize: sectionSize}
+	stored, sections := rawdb.ReadBloomSections(db)
+	if stored != 0 && stored != sectionSize {
+		return nil, fmt.Errorf("bloom index section size mismatch: stored %d, have %d", stored, sectionSize)
+	}
+	idx.sections = sections
+	idx.next = idx.sections * sectionSize
+	idx.reset()
+	return idx, nil
+}
+
+// SectionSize returns the number of blocks per section.
+func (idx *BloomIndex) SectionSize() uint64 { return idx.sectionSize }
+
+// Sections returns the number of sections stored in the database. Blocks
+// below Sections() * SectionSize() are covered by the index.
+func (idx *BloomIndex) Sections() uint64 { return idx.sections }
+
+// Next returns the number of the next header Add expects.
+func (idx *BloomIndex) Next() uint64 { return idx.next }
+
+func (idx *BloomIndex) reset() {
+	idx.bits = make([][]byte, types.BloomBitLength)
+	for i := range idx.bits {
+		idx.bits[i] = make([]byte, idx.sectionSize/8)
+	}
+}
+
+// Add adds the bloom of the given header to the index. Headers must be added
+// in order of their numbers, starting at Next().
+func (idx *BloomIndex) Add(header *types.Header) error {
+	if !header.Number.IsUint64() || header.Number.Uint64() != idx.next {
+		return fmt.Errorf("%w: have %v, want %d", errUnexpectedHead, header.Number, idx.next)
+	}
+	pos := idx.next % idx.sectionSize
+	byteIndex, bitMask := pos/8, byte(1)<<(7-pos%8)
+	for bit := 0; bit < types.BloomBitLength; bit++ {
+		if header.Bloom[types.BloomByteLength-1-bit/8]&(1<<(bit%8)) != 0 {
+			idx.bits[bit][byteIndex] |= bitMask
+		}
+	}
+	idx.next++
+	if pos == idx.sectionSize-1 {
+		return idx.commit()
+	}
+	return nil
+}
+
+// Rollback discards the index data of the block with the given number and
+// all later blocks, e.g. after a reorg replaced them. Stored sections that
+// contain such a block are dropped, as is the section being generated, so
+// Next may move back further than number. Headers must then be added again
+// from Next on.
+func (idx *BloomIndex) Rollback(number uint64) error {
+	if number >= idx.next {
+		return nil
+	}
+	if sections := number / idx.sectionSize; sections < idx.sections {
+		if err := rawdb.WriteBloomSections(idx.db, idx.sectionSize, sections); err != nil {
+			return err
+		}
+		idx.sections = sections
+	}
+	idx.next = idx.sections * idx.sectionSize
+	idx.reset()
+	return nil
+}
+
+// commit writes the completed section to the database.
+func (idx *BloomIndex) commit() error {
+	batch := idx.db.NewBatch()
+	for bit, vector := range idx.bits {
+		if err := rawdb.WriteBloomBits(batch, uint(bit), idx.sections, vector); err != nil {
+			return err
+		}
+	}
+	if err := rawdb.WriteBloomSections(batch, idx.sectionSize, idx.sections+1); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	idx.sections++
+	idx.reset()
+	return nil
+}
+
+// BitVector returns the vector of the given bloom bit in a stored section.
+// Bit i of the vector, counting from the most significant bit of the first
+// byte, is set if block section*SectionSize()+i has the bloom bit set.
+func (idx *BloomIndex) BitVector(bit uint, section uint64) ([]byte, error) {
+	if section >= idx.sections {
+		return nil, fmt.Errorf("bloom section %d not indexed", section)
+	}
+	vector, err := rawdb.ReadBloomBits(idx.db, bit, section)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(vector)) != idx.sectionSize/8 {
+		return nil, fmt.Errorf("%w: bit %d of section %d has %d bytes, want %d", errVectorLength, bit, section, len(vector), idx.sectionSize/8)
+	}
+	return vector, nil
+}
+
+// match returns the bit vector of the blocks in a stored section whose
+// blooms may contain logs matching the criteria. Each group of criteria is
+// a list of alternatives given by their bloom bits; empty groups match
+// everything.
+func (idx *BloomIndex) match(section uint64, groups [][][3]uint) ([]byte, error) {
+	result := make([]byte, idx.sectionSize/8)
+	for i := range result {
+		result[i] = 0xff
+	}
+	cache := make(map[uint][]byte)
+	vector := func(bit uint) ([]byte, error) {
+		if v, ok := cache[bit]; ok {
+			return v, nil
+		}
+		v, err := idx.BitVector(bit, section)
+		if err != nil {
+			return nil, err
+		}
+		cache[bit] = v
+		return v, nil
+	}
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		matched := make([]byte, len(result))
+		for _, bits := range group {
+			all := make([]byte, len(result))
+			for i := range all {
+				all[i] = 0xff
+			}
+			for _, bit := range bits {
+				v, err := vector(bit)
+				if err != nil {
+					return nil, err
+				}
+				for i := range all {
+					all[i] &= v[i]
+				}
+			}
+			for i := range matched {
+				matched[i] |= all[i]
+			}
+		}
+		for i := range result {
+			result[i] &= matched[i]
+		}
+	}
+	return result, nil
+}
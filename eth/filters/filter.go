@@ -0,0 +1,231 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package filters answers log queries over a chain of headers and receipts.
+//
+// A query selects logs by emitting contract and by topics. Header blooms are
+// used to skip blocks which cannot contain matching logs, and a BloomIndex
+// speeds up scans of long block ranges.
+package filters
+
+import (
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+// Backend provides the chain data a Filter reads.
+type Backend interface {
+	// HeaderByNumber returns the canonical header with the given number, or
+	// nil if the chain is shorter.
+	HeaderByNumber(number uint64) (*types.Header, error)
+
+	// GetReceipts returns the receipts of the block with the given hash and
+	// number.
+	GetReceipts(hash common.Hash, number uint64) (types.Receipts, error)
+}
+
+// Filter finds the logs in a block range which match address and topic
+// criteria.
+//
+// A log matches if it was emitted by any of the addresses, or addresses is
+// empty, and if for every position i of topics the log's topic i equals any
+// of topics[i]. An empty topics[i] is a wildcard for that position.
+//
+//	{}                 matches any topic list
+//	{{A}}              matches topic A in first position
+//	{{}, {B}}          matches any topic in first position AND B in second position
+//	{{A}, {B}}         matches topic A in first position AND B in second position
+//	{{A, B}, {C, D}}   matches topic (A OR B) in first position AND (C OR D) in second position
+type Filter struct {
+	backend Backend
+	index   *BloomIndex
+
+	addresses []common.Address
+	topics    [][]common.Hash
+
+	begin, end uint64
+}
+
+// NewRangeFilter creates a filter for the logs of blocks begin to end
+// inclusive. The index is optional; if given, its stored sections are used
+// instead of the header blooms.
+func NewRangeFilter(backend Backend, index *BloomIndex, begin, end uint64, addresses []common.Address, topics [][]common.Hash) *Filter {
+	return &Filter{
+		backend:   backend,
+		index:     index,
+		addresses: addresses,
+		topics:    topics,
+		begin:     begin,
+		end:       end,
+	}
+}
+
+// Logs returns the matching logs in block order. The scan stops early at
+// the head of the chain.
+func (f *Filter) Logs() ([]*types.Log, error) {
+	var logs []*types.Log
+	number := f.begin
+	if f.index != nil {
+		groups := f.bloomGroups()
+		size := f.index.SectionSize()
+		for ; number <= f.end && number/size < f.index.Sections(); number = (number/size + 1) * size {
+			section := number / size
+			vector, err := f.index.match(section, groups)
+			if err != nil {
+				return nil, err
+			}
+			last := (section+1)*size - 1
+			if last > f.end {
+				last = f.end
+			}
+			for n := number; n <= last; n++ {
+				if pos := n - section*size; vector[pos/8]&(1<<(7-pos%8)) == 0 {
+					continue
+				}
+				header, err := f.backend.HeaderByNumber(n)
+				if header == nil || err != nil {
+					return logs, err
+				}
+				found, err := f.blockLogs(header)
+				if err != nil {
+					return logs, err
+				}
+				logs = append(logs, found...)
+			}
+			if last == f.end {
+				return logs, nil
+			}
+		}
+	}
+	matcher := BloomMatcher(f.addresses, f.topics)
+	for ; number <= f.end; number++ {
+		header, err := f.backend.HeaderByNumber(number)
+		if header == nil || err != nil {
+			return logs, err
+		}
+		if !matcher.Match(&header.Bloom) {
+			continue
+		}
+		found, err := f.blockLogs(header)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, found...)
+	}
+	return logs, nil
+}
+
+// blockLogs returns the matching logs of a block whose bloom matched.
+func (f *Filter) blockLogs(header *types.Header) ([]*types.Log, error) {
+	receipts, err := f.backend.GetReceipts(header.Hash(), header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	var unfiltered []*types.Log
+	for _, receipt := range receipts {
+		unfiltered = append(unfiltered, receipt.Logs...)
+	}
+	return FilterLogs(unfiltered, nil, nil, f.addresses, f.topics), nil
+}
+
+// bloomGroups converts the criteria into groups of alternative bloom bits
+// for BloomIndex.match.
+func (f *Filter) bloomGroups() [][][3]uint {
+	groups := make([][][3]uint, 0, len(f.topics)+1)
+	var group [][3]uint
+	for _, addr := range f.addresses {
+		group = append(group, types.NewBloomBits(addr.Bytes()).Bits())
+	}
+	groups = append(groups, group)
+	for _, sub := range f.topics {
+		group = nil
+		for _, topic := range sub {
+			group = append(group, types.NewBloomBits(topic.Bytes()).Bits())
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// BloomFilter reports whether a block with the given bloom may contain logs
+// matching the criteria. To test many blooms, use BloomMatcher instead.
+func BloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
+	return BloomMatcher(addresses, topics).Match(&bloom)
+}
+
+// BloomMatcher returns a matcher for the blooms of blocks which may contain
+// logs matching the criteria.
+func BloomMatcher(addresses []common.Address, topics [][]common.Hash) *types.BloomMatcher {
+	groups := make([][][]byte, 0, len(topics)+1)
+	group := make([][]byte, len(addresses))
+	for i := range addresses {
+		group[i] = addresses[i].Bytes()
+	}
+	groups = append(groups, group)
+	for _, sub := range topics {
+		group = make([][]byte, len(sub))
+		for i := range sub {
+			group[i] = sub[i].Bytes()
+		}
+		groups = append(groups, group)
+	}
+	return types.NewBloomMatcher(groups)
+}
+
+// FilterLogs returns the logs matching the criteria. If fromBlock or toBlock
+// is not nil, logs outside of that block range are dropped as well.
+func FilterLogs(logs []*types.Log, fromBlock, toBlock *uint64, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	var ret []*types.Log
+Logs:
+	for _, log := range logs {
+		if fromBlock != nil && log.BlockNumber < *fromBlock {
+			continue
+		}
+		if toBlock != nil && log.BlockNumber > *toBlock {
+			continue
+		}
+		if len(addresses) > 0 && !includes(addresses, log.Address) {
+			continue
+		}
+		// If the to filtered topics is greater than the amount of topics in logs, skip.
+		if len(topics) > len(log.Topics) {
+			continue
+		}
+		for i, sub := range topics {
+			match := len(sub) == 0 // empty rule set == wildcard
+			for _, topic := range sub {
+				if log.Topics[i] == topic {
+					match = true
+					break
+				}
+			}
+			if !match {
+				continue Logs
+			}
+		}
+		ret = append(ret, log)
+	}
+	return ret
+}
+
+func includes(addresses []common.Address, a common.Address) bool {
+	for _, addr := range addresses {
+		if addr == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/core/rawdb"
+	"github.com/arcology-network/3rd-party/eth/ethdb"
+	"github.com/arcology-network/3rd-party/eth/types"
+)
+
+type testBackend struct {
+	headers  []*types.Header
+	receipts map[common.Hash]types.Receipts
+}
+
+func (b *testBackend) HeaderByNumber(number uint64) (*types.Header, error) {
+	if number >= uint64(len(b.headers)) {
+		return nil, nil
+	}
+	return b.headers[number], nil
+}
+
+func (b *testBackend) GetReceipts(hash common.Hash, number uint64) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+var (
+	testAddrs  = []common.Address{{1}, {2}, {3}}
+	testTopics = []common.Hash{{0xa}, {0xb}, {0xc}, {0xd}}
+)
+
+// newTestChain creates a chain of n blocks with random logs and returns it
+// along with all of its logs.
+func newTestChain(n int, seed int64) (*testBackend, []*types.Log) {
+	rnd := rand.New(rand.NewSource(seed))
+	backend := &testBackend{receipts: make(map[common.Hash]types.Receipts)}
+	var all []*types.Log
+	for i := 0; i < n; i++ {
+		var receipts types.Receipts
+		// Leave some blocks empty.
+		for r := rnd.Intn(3) - 1; r > 0; r-- {
+			receipt := &types.Receipt{}
+			for l := rnd.Intn(3); l > 0; l-- {
+				log := &types.Log{Address: testAddrs[rnd.Intn(len(testAddrs))], BlockNumber: uint64(i)}
+				for t := rnd.Intn(4); t > 0; t-- {
+					log.Topics = append(log.Topics, testTopics[rnd.Intn(len(testTopics))])
+				}
+				receipt.Logs = append(receipt.Logs, log)
+				all = append(all, log)
+			}
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			receipts = append(receipts, receipt)
+		}
+		header := &types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1),
+			Time:       big.NewInt(int64(i)),
+			Bloom:      types.CreateBloom(receipts),
+		}
+		backend.headers = append(backend.headers, header)
+		backend.receipts[header.Hash()] = receipts
+	}
+	return backend, all
+}
+
+func TestFilterLogs(t *testing.T) {
+	logs := []*types.Log{
+		{Address: testAddrs[0], BlockNumber: 1},
+		{Address: testAddrs[0], Topics: []common.Hash{testTopics[0]}, BlockNumber: 2},
+		{Address: testAddrs[1], Topics: []common.Hash{testTopics[0], testTopics[1]}, BlockNumber: 3},
+		{Address: testAddrs[2], Topics: []common.Hash{testTopics[2], testTopics[1]}, BlockNumber: 4},
+	}
+	from, to := uint64(2), uint64(3)
+	tests := []struct {
+		from, to  *uint64
+		addresses []common.Address
+		topics    [][]common.Hash
+		want      []int
+	}{
+		{nil, nil, nil, nil, []int{0, 1, 2, 3}},
+		{&from, &to, nil, nil, []int{1, 2}},
+		{nil, nil, []common.Address{testAddrs[0], testAddrs[2]}, nil, []int{0, 1, 3}},
+		{nil, nil, nil, [][]common.Hash{{testTopics[0]}}, []int{1, 2}},
+		{nil, nil, nil, [][]common.Hash{{}, {testTopics[1]}}, []int{2, 3}},
+		{nil, nil, nil, [][]common.Hash{{testTopics[0], testTopics[2]}, {testTopics[1]}}, []int{2, 3}},
+		{nil, nil, []common.Address{testAddrs[1]}, [][]common.Hash{{testTopics[2]}}, nil},
+		{nil, nil, nil, [][]common.Hash{{}}, []int{1, 2, 3}},
+	}
+	for i, test := range tests {
+		var want []*types.Log
+		for _, j := range test.want {
+			want = append(want, logs[j])
+		}
+		if got := FilterLogs(logs, test.from, test.to, test.addresses, test.topics); !reflect.DeepEqual(got, want) {
+			t.Errorf("test %d: got %d logs, want %v", i, len(got), test.want)
+		}
+	}
+}
+
+func TestRangeFilter(t *testing.T) {
+	backend, all := newTestChain(90, 1)
+	db := ethdb.NewMemDatabase()
+	index, err := NewBloomIndex(db, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, header := range backend.headers {
+		if err := index.Add(header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if index.Sections() != 5 {
+		t.Fatalf("indexed %d sections, want 5", index.Sections())
+	}
+
+	criteria := []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+	}{
+		{nil, nil},
+		{[]common.Address{testAddrs[0]}, nil},
+		{[]common.Address{testAddrs[1], testAddrs[2]}, [][]common.Hash{{testTopics[3]}}},
+		{nil, [][]common.Hash{{}, {testTopics[0], testTopics[1]}}},
+		{nil, [][]common.Hash{{testTopics[2]}, {}, {testTopics[3]}}},
+		{[]common.Address{{0xff}}, nil},
+	}
+	ranges := [][2]uint64{{0, 89}, {0, 200}, {5, 40}, {16, 31}, {70, 85}, {33, 33}, {50, 10}}
+	for _, c := range criteria {
+		for _, r := range ranges {
+			name := fmt.Sprintf("%v/%v/%v", c.addresses, c.topics, r)
+			want := FilterLogs(all, &r[0], &r[1], c.addresses, c.topics)
+			for _, idx := range []*BloomIndex{nil, index} {
+				got, err := NewRangeFilter(backend, idx, r[0], r[1], c.addresses, c.topics).Logs()
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s (indexed %v): got %d logs, want %d", name, idx != nil, len(got), len(want))
+				}
+			}
+		}
+	}
+}
+
+func TestBloomIndexReopen(t *testing.T) {
+	backend, _ := newTestChain(40, 2)
+	db := ethdb.NewMemDatabase()
+	index, _ := NewBloomIndex(db, 16)
+	for _, header := range backend.headers {
+		if err := index.Add(header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := index.Add(backend.headers[3]); err == nil {
+		t.Fatal("out of order header accepted")
+	}
+
+	if _, err := NewBloomIndex(db, 32); err == nil {
+		t.Fatal("section size mismatch not detected")
+	}
+	reopened, err := NewBloomIndex(db, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Sections() != 2 || reopened.Next() != 32 {
+		t.Fatalf("reopened index has %d sections, next %d", reopened.Sections(), reopened.Next())
+	}
+	// Every stored vector bit must agree with the header bloom.
+	for n, header := range backend.headers[:32] {
+		for bit := uint(0); bit < types.BloomBitLength; bit++ {
+			vector, err := reopened.BitVector(bit, uint64(n/16))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pos := n % 16
+			inVector := vector[pos/8]&(1<<(7-pos%8)) != 0
+			inBloom := header.Bloom.Big().Bit(int(bit)) == 1
+			if inVector != inBloom {
+				t.Fatalf("block %d bit %d: vector %v, bloom %v", n, bit, inVector, inBloom)
+			}
+		}
+	}
+	if _, err := reopened.BitVector(0, 2); err == nil {
+		t.Fatal("unindexed section returned")
+	}
+}
+
+func TestBloomIndexRollback(t *testing.T) {
+	old, _ := newTestChain(40, 3)
+	reorged, _ := newTestChain(40, 4)
+	db := ethdb.NewMemDatabase()
+	index, _ := NewBloomIndex(db, 16)
+	for _, header := range old.headers {
+		if err := index.Add(header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Rolling back into the unfinished section keeps the stored ones.
+	if err := index.Rollback(35); err != nil {
+		t.Fatal(err)
+	}
+	if index.Sections() != 2 || index.Next() != 32 {
+		t.Fatalf("after rollback to 35: %d sections, next %d", index.Sections(), index.Next())
+	}
+	if err := index.Rollback(20); err != nil {
+		t.Fatal(err)
+	}
+	if index.Sections() != 1 || index.Next() != 16 {
+		t.Fatalf("after rollback to 20: %d sections, next %d", index.Sections(), index.Next())
+	}
+	if reopened, _ := NewBloomIndex(db, 16); reopened.Sections() != 1 {
+		t.Fatalf("rollback not persisted: %d sections", reopened.Sections())
+	}
+	for _, header := range reorged.headers[16:] {
+		if err := index.Add(header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The second section must reflect the new headers only.
+	for n := 16; n < 32; n++ {
+		for bit := uint(0); bit < types.BloomBitLength; bit++ {
+			vector, err := index.BitVector(bit, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pos := n % 16
+			inVector := vector[pos/8]&(1<<(7-pos%8)) != 0
+			if inBloom := reorged.headers[n].Bloom.Big().Bit(int(bit)) == 1; inVector != inBloom {
+				t.Fatalf("block %d bit %d: vector %v, bloom %v", n, bit, inVector, inBloom)
+			}
+		}
+	}
+}
+
+func TestBloomIndexCorruptVector(t *testing.T) {
+	backend, _ := newTestChain(40, 5)
+	db := ethdb.NewMemDatabase()
+	index, _ := NewBloomIndex(db, 16)
+	for _, header := range backend.headers {
+		if err := index.Add(header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for bit := uint(0); bit < types.BloomBitLength; bit++ {
+		if err := rawdb.WriteBloomBits(db, bit, 1, []byte{0xff}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := index.BitVector(7, 1); !errors.Is(err, errVectorLength) {
+		t.Fatalf("got error %v, want %v", err, errVectorLength)
+	}
+	_, err := NewRangeFilter(backend, index, 0, 39, nil, [][]common.Hash{{testTopics[0]}}).Logs()
+	if !errors.Is(err, errVectorLength) {
+		t.Fatalf("filter returned error %v, want %v", err, errVectorLength)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestIsPetersburg(t *testing.T) {
+	type test struct {
+		constantinople, petersburg *big.Int
+		head                       int64
+		want                       bool
+	}
+	tests := []test{
+		{constantinople: nil, petersburg: nil, head: 100, want: false},
+		{constantinople: big.NewInt(10), petersburg: nil, head: 9, want: false},
+		{constantinople: big.NewInt(10), petersburg: nil, head: 10, want: true},
+		{constantinople: big.NewInt(10), petersburg: big.NewInt(20), head: 10, want: false},
+		{constantinople: big.NewInt(10), petersburg: big.NewInt(20), head: 20, want: true},
+		{constantinople: nil, petersburg: big.NewInt(20), head: 20, want: true},
+	}
+	for i, test := range tests {
+		c := &ChainConfig{ConstantinopleBlock: test.constantinople, PetersburgBlock: test.petersburg}
+		if have := c.IsPetersburg(big.NewInt(test.head)); have != test.want {
+			t.Errorf("test %d: IsPetersburg(%d) = %v, want %v", i, test.head, have, test.want)
+		}
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	type test struct {
+		stored, new *ChainConfig
+		head        uint64
+		wantErr     *ConfigCompatError
+	}
+	tests := []test{
+		{stored: AllEthashProtocolChanges, new: AllEthashProtocolChanges, head: 0, wantErr: nil},
+		{stored: AllEthashProtocolChanges, new: AllEthashProtocolChanges, head: 100, wantErr: nil},
+		{
+			stored:  &ChainConfig{EIP150Block: big.NewInt(10)},
+			new:     &ChainConfig{EIP150Block: big.NewInt(20)},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: AllEthashProtocolChanges,
+			new:    &ChainConfig{HomesteadBlock: nil},
+			head:   3,
+			wantErr: &ConfigCompatError{
+				What:         "Homestead fork block",
+				StoredConfig: big.NewInt(0),
+				NewConfig:    nil,
+				RewindTo:     0,
+			},
+		},
+		{
+			stored: AllEthashProtocolChanges,
+			new:    &ChainConfig{HomesteadBlock: big.NewInt(1)},
+			head:   3,
+			wantErr: &ConfigCompatError{
+				What:         "Homestead fork block",
+				StoredConfig: big.NewInt(0),
+				NewConfig:    big.NewInt(1),
+				RewindTo:     0,
+			},
+		},
+		{
+			stored: &ChainConfig{HomesteadBlock: big.NewInt(30), EIP150Block: big.NewInt(10)},
+			new:    &ChainConfig{HomesteadBlock: big.NewInt(25), EIP150Block: big.NewInt(20)},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "EIP150 fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{DAOForkBlock: big.NewInt(5), DAOForkSupport: true},
+			new:    &ChainConfig{DAOForkBlock: big.NewInt(5), DAOForkSupport: false},
+			head:   10,
+			wantErr: &ConfigCompatError{
+				What:         "DAO fork support flag",
+				StoredConfig: big.NewInt(5),
+				NewConfig:    big.NewInt(5),
+				RewindTo:     4,
+			},
+		},
+		{
+			stored: &ChainConfig{ChainID: big.NewInt(1), EIP158Block: big.NewInt(5)},
+			new:    &ChainConfig{ChainID: big.NewInt(2), EIP158Block: big.NewInt(5)},
+			head:   10,
+			wantErr: &ConfigCompatError{
+				What:         "EIP158 chain ID",
+				StoredConfig: big.NewInt(5),
+				NewConfig:    big.NewInt(5),
+				RewindTo:     4,
+			},
+		},
+		{
+			stored:  &ChainConfig{ConstantinopleBlock: big.NewInt(30)},
+			new:     &ChainConfig{ConstantinopleBlock: big.NewInt(30), PetersburgBlock: big.NewInt(30)},
+			head:    40,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{ConstantinopleBlock: big.NewInt(30)},
+			new:    &ChainConfig{ConstantinopleBlock: big.NewInt(30), PetersburgBlock: big.NewInt(31)},
+			head:   40,
+			wantErr: &ConfigCompatError{
+				What:         "Petersburg fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(31),
+				RewindTo:     30,
+			},
+		},
+		{
+			stored: &ChainConfig{IstanbulBlock: big.NewInt(10)},
+			new:    &ChainConfig{IstanbulBlock: big.NewInt(20)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Istanbul fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{BerlinBlock: big.NewInt(10)},
+			new:    &ChainConfig{BerlinBlock: nil},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Berlin fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{LondonBlock: nil},
+			new:    &ChainConfig{LondonBlock: big.NewInt(10)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "London fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+	}
+	for i, test := range tests {
+		err := test.stored.CheckCompatible(test.new, test.head)
+		if !reflect.DeepEqual(err, test.wantErr) {
+			t.Errorf("test %d: error mismatch:\nstored: %v\nnew: %v\nhead: %v\nerr: %v\nwant: %v", i, test.stored, test.new, test.head, err, test.wantErr)
+		}
+	}
+}
+
+func TestChainConfigRules(t *testing.T) {
+	tests := []struct {
+		config *ChainConfig
+		head   int64
+		want   Rules
+	}{
+		{
+			config: &ChainConfig{},
+			head:   0,
+			want:   Rules{ChainID: new(big.Int)},
+		},
+		{
+			config: MainnetChainConfig,
+			head:   7280000,
+			want: Rules{
+				ChainID:      big.NewInt(1),
+				IsHomestead:  true,
+				IsEIP150:     true,
+				IsEIP155:     true,
+				IsEIP158:     true,
+				IsByzantium:  true,
+				IsPetersburg: true,
+			},
+		},
+		{
+			config: MainnetChainConfig,
+			head:   12965000,
+			want: Rules{
+				ChainID:      big.NewInt(1),
+				IsHomestead:  true,
+				IsEIP150:     true,
+				IsEIP155:     true,
+				IsEIP158:     true,
+				IsByzantium:  true,
+				IsPetersburg: true,
+				IsIstanbul:   true,
+				IsBerlin:     true,
+				IsLondon:     true,
+			},
+		},
+		{
+			config: RinkebyChainConfig,
+			head:   4321234,
+			want: Rules{
+				ChainID:      big.NewInt(4),
+				IsHomestead:  true,
+				IsEIP150:     true,
+				IsEIP155:     true,
+				IsEIP158:     true,
+				IsByzantium:  true,
+				IsPetersburg: true,
+			},
+		},
+	}
+	for i, test := range tests {
+		if have := test.config.Rules(big.NewInt(test.head)); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("test %d: rules mismatch:\nhave: %+v\nwant: %+v", i, have, test.want)
+		}
+	}
+	// The mainnet and Rinkeby configs leave Constantinople unscheduled, so
+	// their gas tables do not change with the later forks.
+	for _, config := range []*ChainConfig{MainnetChainConfig, RinkebyChainConfig} {
+		if config.GasTable(config.LondonBlock) != GasTableEIP158 {
+			t.Errorf("chain %v: gas table changed after Byzantium", config.ChainID)
+		}
+	}
+	// The returned chain ID must not alias the config's.
+	rules := MainnetChainConfig.Rules(big.NewInt(0))
+	rules.ChainID.SetUint64(42)
+	if MainnetChainConfig.ChainID.Uint64() != 1 {
+		t.Fatalf("Rules chain ID aliases the config")
+	}
+}
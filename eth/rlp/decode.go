@@ -82,7 +82,7 @@ type Decoder interface {
 // error if there are too few or too many elements.
 //
 // The decoding of struct fields honours certain struct tags, "tail",
-// "nil" and "-".
+// "nil", "optional" and "-".
 //
 // The "-" tag ignores fields.
 //
@@ -96,6 +96,11 @@ type Decoder interface {
 //         Foo *[20]byte `rlp:"nil"`
 //     }
 //
+// The "optional" tag allows the input list to end before the field. Missing
+// optional fields are set to their zero value. All fields following an
+// optional field must be optional as well, which makes the tag suitable
+// for extending existing types with new trailing fields.
+//
 // To decode into a slice, the input must be a list and the resulting
 // slice will contain the input elements in order. For byte slices,
 // the input must be an RLP string. Array types decode similarly, with
@@ -146,6 +151,19 @@ func DecodeBytes(b []byte, val interface{}) error {
 	return nil
 }
 
+// DecodeBytesStrict is like DecodeBytes, but rejects any input that is not
+// canonical RLP with a *CanonError holding the offset and reason of the
+// first violation. In addition to the checks of Validate, this covers
+// integer values encoded with leading zero bytes.
+func DecodeBytesStrict(b []byte, val interface{}) error {
+	if err := Validate(b); err != nil {
+		return err
+	}
+	s := NewStream(bytes.NewReader(b), uint64(len(b)))
+	s.SetStrict(true)
+	return s.Decode(val)
+}
+
 type decodeError struct {
 	msg string
 	typ reflect.Type
@@ -294,7 +312,7 @@ func decodeBigInt(s *Stream, val reflect.Value) error {
 	}
 	// Reject leading zero bytes
 	if len(b) > 0 && b[0] == 0 {
-		return wrapStreamError(ErrCanonInt, val.Type())
+		return wrapStreamError(s.canonError(ErrCanonInt), val.Type())
 	}
 	i.SetBytes(b)
 	return nil
@@ -435,7 +453,7 @@ func decodeByteArray(s *Stream, val reflect.Value) error {
 		}
 		// Reject cases where single byte encoding should have been used.
 		if size == 1 && slice[0] < 128 {
-			return wrapStreamError(ErrCanonSize, val.Type())
+			return wrapStreamError(s.canonError(ErrCanonSize), val.Type())
 		}
 	case List:
 		return wrapStreamError(ErrExpectedString, val.Type())
@@ -452,10 +470,18 @@ func makeStructDecoder(typ reflect.Type) (decoder, error) {
 		if _, err := s.List(); err != nil {
 			return wrapStreamError(err, typ)
 		}
-		for _, f := range fields {
+		for i, f := range fields {
 			err := f.info.decoder(s, val.Field(f.index))
 			if err == EOL {
-				return &decodeError{msg: "too few elements", typ: typ}
+				if !f.optional {
+					return &decodeError{msg: "too few elements", typ: typ}
+				}
+				// The input list ends before the optional fields, reset them.
+				for _, f := range fields[i:] {
+					fv := val.Field(f.index)
+					fv.Set(reflect.Zero(fv.Type()))
+				}
+				break
 			} else if err != nil {
 				return addErrorContext(err, "."+typ.Field(f.index).Name)
 			}
@@ -619,6 +645,10 @@ type Stream struct {
 	byteval byte   // value of single byte in type tag
 	kinderr error  // error from last readKind
 	stack   []listpos
+
+	pos     uint64 // number of bytes read from r
+	kindpos uint64 // offset of the type tag of the value ahead
+	strict  bool   // report canonical violations as *CanonError
 }
 
 type listpos struct{ pos, size uint64 }
@@ -674,7 +704,7 @@ func (s *Stream) Bytes() ([]byte, error) {
 			return nil, err
 		}
 		if size == 1 && b[0] < 128 {
-			return nil, ErrCanonSize
+			return nil, s.canonError(ErrCanonSize)
 		}
 		return b, nil
 	default:
@@ -722,7 +752,7 @@ func (s *Stream) uint(maxbits int) (uint64, error) {
 	switch kind {
 	case Byte:
 		if s.byteval == 0 {
-			return 0, ErrCanonInt
+			return 0, s.canonError(ErrCanonInt)
 		}
 		s.kind = -1 // rearm Kind
 		return uint64(s.byteval), nil
@@ -734,11 +764,11 @@ func (s *Stream) uint(maxbits int) (uint64, error) {
 		switch {
 		case err == ErrCanonSize:
 			// Adjust error because we're not reading a size right now.
-			return 0, ErrCanonInt
+			return 0, s.canonError(ErrCanonInt)
 		case err != nil:
 			return 0, err
 		case size > 0 && v < 128:
-			return 0, ErrCanonSize
+			return 0, s.canonError(ErrCanonSize)
 		default:
 			return v, nil
 		}
@@ -765,6 +795,87 @@ func (s *Stream) Bool() (bool, error) {
 	}
 }
 
+// Uint64 reads an RLP string of up to 8 bytes and returns its contents
+// as an unsigned integer.
+func (s *Stream) Uint64() (uint64, error) {
+	return s.uint(64)
+}
+
+// Uint32 reads an RLP string of up to 4 bytes and returns its contents
+// as an unsigned integer.
+func (s *Stream) Uint32() (uint32, error) {
+	i, err := s.uint(32)
+	return uint32(i), err
+}
+
+// Uint16 reads an RLP string of up to 2 bytes and returns its contents
+// as an unsigned integer.
+func (s *Stream) Uint16() (uint16, error) {
+	i, err := s.uint(16)
+	return uint16(i), err
+}
+
+// Uint8 reads an RLP string of up to 1 byte and returns its contents
+// as an unsigned integer.
+func (s *Stream) Uint8() (uint8, error) {
+	i, err := s.uint(8)
+	return uint8(i), err
+}
+
+// BigInt decodes an arbitrary-size integer value. Leading zero bytes
+// are rejected with ErrCanonInt.
+func (s *Stream) BigInt() (*big.Int, error) {
+	b, err := s.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 0 && b[0] == 0 {
+		return nil, s.canonError(ErrCanonInt)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// ReadBytes decodes the next RLP value and stores the result in b.
+// The value size must match len(b) exactly.
+func (s *Stream) ReadBytes(b []byte) error {
+	kind, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case Byte:
+		if len(b) != 1 {
+			return fmt.Errorf("rlp: input value has wrong size 1, want %d", len(b))
+		}
+		b[0] = s.byteval
+		s.kind = -1 // rearm Kind
+		return nil
+	case String:
+		if uint64(len(b)) != size {
+			return fmt.Errorf("rlp: input value has wrong size %d, want %d", size, len(b))
+		}
+		if err = s.readFull(b); err != nil {
+			return err
+		}
+		if size == 1 && b[0] < 128 {
+			return s.canonError(ErrCanonSize)
+		}
+		return nil
+	default:
+		return ErrExpectedString
+	}
+}
+
+// MoreDataInList reports whether the current list has more elements
+// to read. It returns false outside of any list.
+func (s *Stream) MoreDataInList() bool {
+	if len(s.stack) == 0 {
+		return false
+	}
+	tos := s.stack[len(s.stack)-1]
+	return tos.pos < tos.size
+}
+
 // List starts decoding an RLP list. If the input does not contain a
 // list, the returned error will be ErrExpectedList. When the list's
 // end has been reached, any Stream operation will return EOL.
@@ -864,11 +975,45 @@ func (s *Stream) Reset(r io.Reader, inputLimit uint64) {
 	s.size = 0
 	s.kind = -1
 	s.kinderr = nil
+	s.pos = 0
+	s.kindpos = 0
+	s.strict = false
 	if s.uintbuf == nil {
 		s.uintbuf = make([]byte, 8)
 	}
 }
 
+// SetStrict enables or disables strict mode. In strict mode, values that
+// are not canonically encoded, including integers with leading zero bytes,
+// fail with a *CanonError holding the offset of the value in the input.
+// Strict mode is cleared by Reset.
+func (s *Stream) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// Strict reports whether the stream is in strict mode.
+func (s *Stream) Strict() bool {
+	return s.strict
+}
+
+// Offset returns the number of input bytes consumed by the stream.
+func (s *Stream) Offset() uint64 {
+	return s.pos
+}
+
+// canonError attaches the offset of the current value to a canonical
+// encoding violation if the stream is in strict mode.
+func (s *Stream) canonError(err error) error {
+	if !s.strict {
+		return err
+	}
+	switch err {
+	case ErrCanonSize, ErrCanonInt, ErrElemTooLarge:
+		return &CanonError{Offset: int(s.kindpos), Err: err}
+	}
+	return err
+}
+
 // Kind returns the kind and size of the next value in the
 // input stream.
 //
@@ -892,6 +1037,7 @@ func (s *Stream) Kind() (kind Kind, size uint64, err error) {
 		if tos != nil && tos.pos == tos.size {
 			return 0, 0, EOL
 		}
+		s.kindpos = s.pos
 		s.kind, s.size, s.kinderr = s.readKind()
 		if s.kinderr == nil {
 			if tos == nil {
@@ -907,6 +1053,7 @@ func (s *Stream) Kind() (kind Kind, size uint64, err error) {
 				}
 			}
 		}
+		s.kinderr = s.canonError(s.kinderr)
 	}
 	// Note: this might return a sticky error generated
 	// by an earlier call to readKind.
@@ -1044,5 +1191,6 @@ func (s *Stream) willRead(n uint64) error {
 		}
 		s.remaining -= n
 	}
+	s.pos += n
 	return nil
 }
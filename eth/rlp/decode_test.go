@@ -118,6 +118,16 @@ func TestStreamErrors(t *testing.T) {
 		{"817F", calls{"Uint"}, nil, ErrCanonSize},
 		{"8180", calls{"Uint"}, nil, nil},
 
+		// Sized integers
+		{"820100", calls{"Uint8"}, nil, errUintOverflow},
+		{"83010000", calls{"Uint16"}, nil, errUintOverflow},
+		{"8400000001", calls{"Uint32"}, nil, ErrCanonInt},
+		{"850100000000", calls{"Uint32"}, nil, errUintOverflow},
+		{"83FFFFFF", calls{"Uint32"}, nil, nil},
+		{"C0", calls{"Uint64"}, nil, ErrExpectedString},
+		{"820001", calls{"BigInt"}, nil, ErrCanonInt},
+		{"C0", calls{"BigInt"}, nil, ErrExpectedString},
+
 		// Non-valid boolean
 		{"02", calls{"Bool"}, nil, errors.New("rlp: invalid boolean value: 2")},
 
@@ -238,6 +248,9 @@ func TestStreamList(t *testing.T) {
 	}
 
 	for i := uint64(1); i <= 8; i++ {
+		if !s.MoreDataInList() {
+			t.Fatalf("MoreDataInList returned false before element %d", i)
+		}
 		v, err := s.Uint()
 		if err != nil {
 			t.Fatalf("Uint error: %v", err)
@@ -247,6 +260,9 @@ func TestStreamList(t *testing.T) {
 		}
 	}
 
+	if s.MoreDataInList() {
+		t.Errorf("MoreDataInList returned true at end of list")
+	}
 	if _, err := s.Uint(); err != EOL {
 		t.Errorf("Uint error mismatch, got %v, want %v", err, EOL)
 	}
@@ -284,6 +300,35 @@ func TestStreamRaw(t *testing.T) {
 	}
 }
 
+func TestStreamReadBytes(t *testing.T) {
+	tests := []struct {
+		input string
+		size  int
+		err   string
+	}{
+		{"C0", 1, "rlp: expected String or Byte"},
+		{"04", 0, "rlp: input value has wrong size 1, want 0"},
+		{"04", 1, ""},
+		{"8104", 1, "rlp: non-canonical size information"},
+		{"820102", 3, "rlp: input value has wrong size 2, want 3"},
+		{"83010203", 3, ""},
+	}
+	for i, tt := range tests {
+		s := NewStream(bytes.NewReader(unhex(tt.input)), 0)
+		b := make([]byte, tt.size)
+		err := s.ReadBytes(b)
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("test %d: unexpected error: %v", i, err)
+			} else if want := unhex(tt.input); !bytes.Equal(b, want[len(want)-tt.size:]) {
+				t.Errorf("test %d: wrong bytes %x", i, b)
+			}
+		} else if err == nil || err.Error() != tt.err {
+			t.Errorf("test %d: error mismatch: got %v, want %q", i, err, tt.err)
+		}
+	}
+}
+
 func TestDecodeErrors(t *testing.T) {
 	r := bytes.NewReader(nil)
 
@@ -347,6 +392,42 @@ type tailUint struct {
 	Tail []uint `rlp:"tail"`
 }
 
+type optionalFields struct {
+	A uint
+	B uint `rlp:"optional"`
+	C uint `rlp:"optional"`
+}
+
+type optionalAndTailField struct {
+	A    uint
+	B    uint   `rlp:"optional"`
+	Tail []uint `rlp:"tail"`
+}
+
+type optionalBigIntField struct {
+	A uint
+	B *big.Int `rlp:"optional"`
+}
+
+type optionalPtrField struct {
+	A uint
+	B *[3]byte `rlp:"optional"`
+}
+
+type nonOptionalPtrField struct {
+	A uint
+	B *[3]byte
+}
+
+type invalidOptional1 struct {
+	A uint `rlp:"optional"`
+	B uint
+}
+
+type invalidOptional2 struct {
+	A []uint `rlp:"optional,tail"`
+}
+
 var (
 	veryBigInt = big.NewInt(0).Add(
 		big.NewInt(0).Lsh(big.NewInt(0xFFFFFFFFFFFFFF), 16),
@@ -511,6 +592,78 @@ var decodeTests = []decodeTest{
 		value: tailRaw{A: 1, Tail: []RawValue{}},
 	},
 
+	// struct tag "optional"
+	{
+		input: "C101",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 0, 0},
+	},
+	{
+		input: "C20102",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 2, 0},
+	},
+	{
+		input: "C3010203",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 2, 3},
+	},
+	{
+		input: "C401020304",
+		ptr:   new(optionalFields),
+		error: "rlp: input list has too many elements for rlp.optionalFields",
+	},
+	{
+		input: "C0",
+		ptr:   new(optionalFields),
+		error: "rlp: too few elements for rlp.optionalFields",
+	},
+	{
+		input: "C101",
+		ptr:   &optionalFields{A: 9, B: 9, C: 9},
+		value: optionalFields{1, 0, 0},
+	},
+	{
+		input: "C101",
+		ptr:   new(optionalBigIntField),
+		value: optionalBigIntField{A: 1, B: nil},
+	},
+	{
+		input: "C20102",
+		ptr:   new(optionalBigIntField),
+		value: optionalBigIntField{A: 1, B: big.NewInt(2)},
+	},
+	{
+		input: "C101",
+		ptr:   new(optionalPtrField),
+		value: optionalPtrField{A: 1},
+	},
+	{
+		input: "C50183010203",
+		ptr:   new(optionalPtrField),
+		value: optionalPtrField{A: 1, B: &[3]byte{1, 2, 3}},
+	},
+	{
+		input: "C101",
+		ptr:   new(nonOptionalPtrField),
+		error: "rlp: too few elements for rlp.nonOptionalPtrField",
+	},
+	{
+		input: "C0",
+		ptr:   new(invalidOptional1),
+		error: "rlp: invalid struct tag \"\" for rlp.invalidOptional1.B (must be optional because preceding field \"A\" is optional)",
+	},
+	{
+		input: "C0",
+		ptr:   new(invalidOptional2),
+		error: "rlp: invalid struct tag \"optional\" for rlp.invalidOptional2.A (also has \"tail\" tag)",
+	},
+	{
+		input: "C0",
+		ptr:   new(optionalAndTailField),
+		error: "rlp: invalid struct tag \"\" for rlp.optionalAndTailField.Tail (must be optional because preceding field \"B\" is optional)",
+	},
+
 	// struct tag "-"
 	{
 		input: "C20102",
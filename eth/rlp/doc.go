@@ -29,5 +29,13 @@ array).
 RLP values are distinguished by a type tag. The type tag precedes the
 value in the input stream and defines the size and kind of the bytes
 that follow.
+
+Encoding and decoding through reflection has a cost on hot paths. The
+rlpgen command in the rlpgen subdirectory generates EncodeRLP and
+DecodeRLP methods for struct types which produce the same encoding
+without reflection.
+
+To inspect encoded data of unknown type, Dump and DumpJSON print the
+value structure. The rlpdump command exposes them on the command line.
 */
 package rlp
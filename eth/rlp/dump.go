@@ -0,0 +1,154 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Dump writes a human-readable tree of the RLP values in b to w. It does
+// not need to know the type the input was encoded from: lists are shown
+// as nested brackets and strings in hex, annotated with guesses about
+// their meaning (integer, text, address or hash).
+func Dump(w io.Writer, b []byte) error {
+	for len(b) > 0 {
+		rest, err := dumpValue(w, b, "")
+		if err != nil {
+			return err
+		}
+		b = rest
+	}
+	return nil
+}
+
+func dumpValue(w io.Writer, b []byte, indent string) ([]byte, error) {
+	kind, content, rest, err := Split(b)
+	if err != nil {
+		return b, err
+	}
+	if kind != List {
+		line := indent + "0x" + hex.EncodeToString(content)
+		if notes := describeString(content); len(notes) > 0 {
+			line += "  // " + strings.Join(notes, ", ")
+		}
+		_, err := fmt.Fprintln(w, line)
+		return rest, err
+	}
+	if len(content) == 0 {
+		_, err := fmt.Fprintln(w, indent+"[]")
+		return rest, err
+	}
+	if _, err := fmt.Fprintln(w, indent+"["); err != nil {
+		return rest, err
+	}
+	for len(content) > 0 {
+		if content, err = dumpValue(w, content, indent+"  "); err != nil {
+			return rest, err
+		}
+	}
+	_, err = fmt.Fprintln(w, indent+"]")
+	return rest, err
+}
+
+// describeString returns the most plausible interpretation of the content
+// of an RLP string.
+func describeString(b []byte) []string {
+	var notes []string
+	switch {
+	case len(b) == 20:
+		notes = append(notes, "address")
+	case len(b) == 32:
+		notes = append(notes, "hash")
+	case isPrintable(b):
+		notes = append(notes, strconv.Quote(string(b)))
+	case isCanonicalInt(b):
+		notes = append(notes, "int "+new(big.Int).SetBytes(b).String())
+	}
+	return notes
+}
+
+// isCanonicalInt reports whether b is a plausible integer encoding, i.e.
+// at most 32 bytes without leading zeros.
+func isCanonicalInt(b []byte) bool {
+	return len(b) <= 32 && (len(b) == 0 || b[0] != 0)
+}
+
+func isPrintable(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// DumpJSON renders the RLP values in b as JSON. Lists become arrays and
+// strings become numbers if they look like small integers, text if they
+// are printable and hex otherwise. If b holds more than one value, the
+// result is an array of all values.
+func DumpJSON(b []byte) ([]byte, error) {
+	var values []interface{}
+	for len(b) > 0 {
+		v, rest, err := jsonValue(b)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		b = rest
+	}
+	if len(values) == 1 {
+		return json.MarshalIndent(values[0], "", "  ")
+	}
+	return json.MarshalIndent(values, "", "  ")
+}
+
+func jsonValue(b []byte) (interface{}, []byte, error) {
+	kind, content, rest, err := Split(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if kind == List {
+		list := []interface{}{}
+		for len(content) > 0 {
+			var v interface{}
+			if v, content, err = jsonValue(content); err != nil {
+				return nil, rest, err
+			}
+			list = append(list, v)
+		}
+		return list, rest, nil
+	}
+	switch {
+	case len(content) == 20 || len(content) == 32:
+		return "0x" + hex.EncodeToString(content), rest, nil
+	case isPrintable(content):
+		return string(content), rest, nil
+	case len(content) <= 8 && isCanonicalInt(content):
+		return json.Number(new(big.Int).SetBytes(content).String()), rest, nil
+	default:
+		return "0x" + hex.EncodeToString(content), rest, nil
+	}
+}
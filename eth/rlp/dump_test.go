@@ -0,0 +1,77 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	input := unhex("F8420A8568656C6C6F94" + strings.Repeat("AA", 20) + "A0" + strings.Repeat("BB", 32) + "C3C20102C0")
+	want := `[
+  0x0a  // int 10
+  0x68656c6c6f  // "hello"
+  0x` + strings.Repeat("aa", 20) + `  // address
+  0x` + strings.Repeat("bb", 32) + `  // hash
+  [
+    [
+      0x01  // int 1
+      0x02  // int 2
+    ]
+  ]
+  []
+]
+`
+	var buf bytes.Buffer
+	if err := Dump(&buf, input); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("wrong output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	if err := Dump(&buf, unhex("C3C2")); err == nil {
+		t.Error("expected error for truncated input")
+	}
+}
+
+func TestDumpJSON(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"80", `0`},
+		{"C0", `[]`},
+		{"820400", `1024`},
+		{"8568656C6C6F", `"hello"`},
+		{"830001FF", `"0x0001ff"`},
+		{"0102", "[\n  1,\n  2\n]"},
+		{"C2C101", "[\n  [\n    1\n  ]\n]"},
+		{"94" + strings.Repeat("AA", 20), `"0x` + strings.Repeat("aa", 20) + `"`},
+	}
+	for _, test := range tests {
+		out, err := DumpJSON(unhex(test.input))
+		if err != nil {
+			t.Errorf("input %s: error %v", test.input, err)
+			continue
+		}
+		if string(out) != test.want {
+			t.Errorf("input %s: got %s, want %s", test.input, out, test.want)
+		}
+	}
+}
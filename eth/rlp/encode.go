@@ -17,6 +17,7 @@
 package rlp
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -25,6 +26,9 @@ import (
 	"unsafe"
 )
 
+// ErrNegativeBigInt is returned when encoding a negative *big.Int.
+var ErrNegativeBigInt = errors.New("rlp: cannot encode negative *big.Int")
+
 var (
 	// Common encoded values.
 	// These are useful when implementing EncodeRLP.
@@ -63,7 +67,9 @@ type Encoder interface {
 // if the array has element type byte).
 //
 // Struct values are encoded as an RLP list of all their encoded
-// public fields. Recursive struct types are supported.
+// public fields. Recursive struct types are supported. Trailing fields
+// with the "optional" struct tag are omitted while they hold the zero
+// value.
 //
 // To encode slices and arrays, the elements are encoded as an RLP
 // list of the value's elements. Note that arrays and slices with
@@ -79,7 +85,7 @@ type Encoder interface {
 // Boolean values are not supported, nor are signed integers, floating
 // point numbers, maps, channels and functions.
 func Encode(w io.Writer, val interface{}) error {
-	if outer, ok := w.(*encbuf); ok {
+	if outer := encbufFromWriter(w); outer != nil {
 		// Encode was called by some type's EncodeRLP.
 		// Avoid copying by writing to the outer encbuf directly.
 		return outer.encode(val)
@@ -120,10 +126,10 @@ func EncodeToReader(val interface{}) (size int, r io.Reader, err error) {
 }
 
 type encbuf struct {
-	str     []byte      // string data, contains everything except list headers
-	lheads  []*listhead // all list headers
-	lhsize  int         // sum of sizes of all encoded list headers
-	sizebuf []byte      // 9-byte auxiliary buffer for uint encoding
+	str     []byte     // string data, contains everything except list headers
+	lheads  []listhead // all list headers
+	lhsize  int        // sum of sizes of all encoded list headers
+	sizebuf []byte     // 9-byte auxiliary buffer for uint encoding
 }
 
 type listhead struct {
@@ -209,13 +215,47 @@ func (w *encbuf) encodeString(b []byte) {
 	}
 }
 
-func (w *encbuf) list() *listhead {
-	lh := &listhead{offset: len(w.str), size: w.lhsize}
-	w.lheads = append(w.lheads, lh)
-	return lh
+func (w *encbuf) writeUint64(i uint64) {
+	if i == 0 {
+		w.str = append(w.str, 0x80)
+	} else if i < 128 {
+		// fits single byte
+		w.str = append(w.str, byte(i))
+	} else {
+		// TODO: encode int to w.str directly
+		s := putint(w.sizebuf[1:], i)
+		w.sizebuf[0] = 0x80 + byte(s)
+		w.str = append(w.str, w.sizebuf[:s+1]...)
+	}
+}
+
+func (w *encbuf) writeBool(b bool) {
+	if b {
+		w.str = append(w.str, 0x01)
+	} else {
+		w.str = append(w.str, 0x80)
+	}
+}
+
+func (w *encbuf) writeString(s string) {
+	if len(s) == 1 && s[0] <= 0x7f {
+		// fits single byte, no string header
+		w.str = append(w.str, s[0])
+	} else {
+		w.encodeStringHeader(len(s))
+		w.str = append(w.str, s...)
+	}
+}
+
+// list starts a new list and returns the index of its header, which
+// must be passed to listEnd once all list elements have been written.
+func (w *encbuf) list() int {
+	w.lheads = append(w.lheads, listhead{offset: len(w.str), size: w.lhsize})
+	return len(w.lheads) - 1
 }
 
-func (w *encbuf) listEnd(lh *listhead) {
+func (w *encbuf) listEnd(index int) {
+	lh := &w.lheads[index]
 	lh.size = w.size() - lh.offset - lh.size
 	if lh.size < 56 {
 		w.lhsize++ // length encoded into kind tag
@@ -232,7 +272,8 @@ func (w *encbuf) toBytes() []byte {
 	out := make([]byte, w.size())
 	strpos := 0
 	pos := 0
-	for _, head := range w.lheads {
+	for i := range w.lheads {
+		head := &w.lheads[i]
 		// write string data before header
 		n := copy(out[pos:], w.str[strpos:head.offset])
 		pos += n
@@ -248,7 +289,8 @@ func (w *encbuf) toBytes() []byte {
 
 func (w *encbuf) toWriter(out io.Writer) (err error) {
 	strpos := 0
-	for _, head := range w.lheads {
+	for i := range w.lheads {
+		head := &w.lheads[i]
 		// write string data before header
 		if head.offset-strpos > 0 {
 			n, err := out.Write(w.str[strpos:head.offset])
@@ -315,7 +357,7 @@ func (r *encReader) next() []byte {
 
 	case r.lhpos < len(r.buf.lheads):
 		// We're before the last list header.
-		head := r.buf.lheads[r.lhpos]
+		head := &r.buf.lheads[r.lhpos]
 		sizebefore := head.offset - r.strpos
 		if sizebefore > 0 {
 			// String data before header.
@@ -391,20 +433,10 @@ func writeRawValue(val reflect.Value, w *encbuf) error {
 }
 
 func writeUint(val reflect.Value, w *encbuf) error {
-	i := val.Uint()
-	if i == 0 {
-		w.str = append(w.str, 0x80)
-	} else if i < 128 {
-		// fits single byte
-		w.str = append(w.str, byte(i))
-	} else {
-		// TODO: encode int to w.str directly
-		s := putint(w.sizebuf[1:], i)
-		w.sizebuf[0] = 0x80 + byte(s)
-		w.str = append(w.str, w.sizebuf[:s+1]...)
-	}
+	w.writeUint64(val.Uint())
 	return nil
 }
+
 func writeInt(val reflect.Value, w *encbuf) error {
 	i := val.Int()
 	if i == 0 {
@@ -423,11 +455,7 @@ func writeInt(val reflect.Value, w *encbuf) error {
 }
 
 func writeBool(val reflect.Value, w *encbuf) error {
-	if val.Bool() {
-		w.str = append(w.str, 0x01)
-	} else {
-		w.str = append(w.str, 0x80)
-	}
+	w.writeBool(val.Bool())
 	return nil
 }
 
@@ -447,7 +475,7 @@ func writeBigIntNoPtr(val reflect.Value, w *encbuf) error {
 
 func writeBigInt(i *big.Int, w *encbuf) error {
 	if cmp := i.Cmp(big0); cmp == -1 {
-		return fmt.Errorf("rlp: cannot encode negative *big.Int")
+		return ErrNegativeBigInt
 	} else if cmp == 0 {
 		w.str = append(w.str, 0x80)
 	} else {
@@ -476,14 +504,7 @@ func writeByteArray(val reflect.Value, w *encbuf) error {
 }
 
 func writeString(val reflect.Value, w *encbuf) error {
-	s := val.String()
-	if len(s) == 1 && s[0] <= 0x7f {
-		// fits single byte, no string header
-		w.str = append(w.str, s[0])
-	} else {
-		w.encodeStringHeader(len(s))
-		w.str = append(w.str, s...)
-	}
+	w.writeString(val.String())
 	return nil
 }
 
@@ -548,9 +569,17 @@ func makeStructWriter(typ reflect.Type) (writer, error) {
 	if err != nil {
 		return nil, err
 	}
+	firstOptional := firstOptionalField(fields)
 	writer := func(val reflect.Value, w *encbuf) error {
+		// Trailing optional fields are omitted while they are zero.
+		last := len(fields) - 1
+		for ; last >= firstOptional; last-- {
+			if !val.Field(fields[last].index).IsZero() {
+				break
+			}
+		}
 		lh := w.list()
-		for _, f := range fields {
+		for _, f := range fields[:last+1] {
 			if err := f.info.writer(val.Field(f.index), w); err != nil {
 				return err
 			}
@@ -219,6 +219,15 @@ var encTests = []encTest{
 	{val: &tailRaw{A: 1, Tail: []RawValue{}}, output: "C101"},
 	{val: &tailRaw{A: 1, Tail: nil}, output: "C101"},
 	{val: &hasIgnoredField{A: 1, B: 2, C: 3}, output: "C20103"},
+	{val: &optionalFields{A: 1}, output: "C101"},
+	{val: &optionalFields{A: 1, B: 2}, output: "C20102"},
+	{val: &optionalFields{A: 1, B: 2, C: 3}, output: "C3010203"},
+	{val: &optionalFields{A: 1, B: 0, C: 3}, output: "C3018003"},
+	{val: &optionalBigIntField{A: 1}, output: "C101"},
+	{val: &optionalBigIntField{A: 1, B: big.NewInt(0)}, output: "C20180"},
+	{val: &optionalPtrField{A: 1}, output: "C101"},
+	{val: &optionalPtrField{A: 1, B: &[3]byte{1, 2, 3}}, output: "C50183010203"},
+	{val: &invalidOptional1{}, error: "rlp: invalid struct tag \"\" for rlp.invalidOptional1.B (must be optional because preceding field \"A\" is optional)"},
 
 	// nil
 	{val: (*uint)(nil), output: "80"},
@@ -339,3 +348,87 @@ func TestEncodeToReaderReturnToPool(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+type bufferEncoder struct {
+	A uint64
+	B *big.Int
+	C []byte
+	D string
+	E []uint64
+}
+
+func (e *bufferEncoder) EncodeRLP(w io.Writer) error {
+	buf := NewEncoderBuffer(w)
+	l := buf.List()
+	buf.WriteUint64(e.A)
+	buf.WriteBigInt(e.B)
+	buf.WriteBytes(e.C)
+	buf.WriteString(e.D)
+	inner := buf.List()
+	for _, v := range e.E {
+		buf.WriteUint64(v)
+	}
+	buf.ListEnd(inner)
+	buf.ListEnd(l)
+	return buf.Flush()
+}
+
+func TestEncoderBuffer(t *testing.T) {
+	val := &bufferEncoder{
+		A: 1024,
+		B: big.NewInt(0xFFFFFF),
+		C: bytes.Repeat([]byte{0xAA}, 60),
+		D: "x",
+		E: []uint64{0, 1, 0x80},
+	}
+	// The reflection-based encoding of the same fields.
+	type plain struct {
+		A uint64
+		B *big.Int
+		C []byte
+		D string
+		E []uint64
+	}
+	want, err := EncodeToBytes(&plain{val.A, val.B, val.C, val.D, val.E})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Top-level encoding into an io.Writer.
+	b := new(bytes.Buffer)
+	if err := val.EncodeRLP(b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Errorf("EncodeRLP output mismatch:\ngot  %X\nwant %X", b.Bytes(), want)
+	}
+
+	// Nested in a reflected value, writing into the outer buffer.
+	nested, err := EncodeToBytes([]interface{}{val, uint(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNested, _ := EncodeToBytes([]interface{}{&plain{val.A, val.B, val.C, val.D, val.E}, uint(1)})
+	if !bytes.Equal(nested, wantNested) {
+		t.Errorf("nested output mismatch:\ngot  %X\nwant %X", nested, wantNested)
+	}
+
+	// ToBytes on a buffer without destination.
+	buf := NewEncoderBuffer(nil)
+	buf.WriteBool(true)
+	buf.WriteBool(false)
+	if out := buf.ToBytes(); !bytes.Equal(out, []byte{0x01, 0x80}) {
+		t.Errorf("ToBytes output mismatch: got %X", out)
+	}
+	buf.Flush()
+}
+
+func BenchmarkEncoderBuffer(b *testing.B) {
+	val := &bufferEncoder{A: 1024, B: big.NewInt(0xFFFFFF), C: make([]byte, 32), D: "test", E: []uint64{1, 2, 3}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := val.EncodeRLP(ioutil.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
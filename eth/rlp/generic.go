@@ -0,0 +1,150 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"errors"
+	"io"
+)
+
+var errIndexRange = errors.New("rlp: list index out of range")
+
+// DecodeAs parses RLP-encoded data into a new value of type T. The input
+// must contain exactly one value and no trailing data.
+func DecodeAs[T any](b []byte) (T, error) {
+	var v T
+	err := DecodeBytes(b, &v)
+	return v, err
+}
+
+// EncodeSlice returns the encoding of s as an RLP list.
+func EncodeSlice[T any](s []T) ([]byte, error) {
+	return EncodeToBytes(s)
+}
+
+// RawList holds an encoded RLP list whose elements have type T. Elements
+// are decoded only when they are accessed, which makes RawList useful for
+// large lists of which only a few elements are needed.
+//
+// The zero value is an empty list. RawList implements Encoder and Decoder,
+// so it can be used in place of a []T field of an RLP struct. Encoding works
+// on values as well as pointers; only Append and DecodeRLP modify the list.
+type RawList[T any] struct {
+	enc []byte // encoding of the list, nil means empty
+}
+
+// NewRawList encodes the given elements into a RawList.
+func NewRawList[T any](items []T) (RawList[T], error) {
+	enc, err := EncodeSlice(items)
+	if err != nil {
+		return RawList[T]{}, err
+	}
+	return RawList[T]{enc: enc}, nil
+}
+
+// Bytes returns the encoding of the list.
+func (r RawList[T]) Bytes() []byte {
+	if r.enc == nil {
+		return EmptyList
+	}
+	return r.enc
+}
+
+// Content returns the encoded elements without the list header.
+func (r RawList[T]) Content() []byte {
+	content, _, _ := SplitList(r.Bytes())
+	return content
+}
+
+// Len returns the number of elements in the list.
+func (r RawList[T]) Len() int {
+	n, _ := CountValues(r.Content())
+	return n
+}
+
+// Get decodes the element at index i.
+func (r RawList[T]) Get(i int) (T, error) {
+	var v T
+	it, err := NewListIterator(r.Bytes())
+	if err != nil {
+		return v, err
+	}
+	for n := 0; it.Next(); n++ {
+		if n == i {
+			err := DecodeBytes(it.Value(), &v)
+			return v, err
+		}
+	}
+	if it.Err() != nil {
+		return v, it.Err()
+	}
+	return v, errIndexRange
+}
+
+// Items decodes all elements of the list.
+func (r RawList[T]) Items() ([]T, error) {
+	var items []T
+	err := DecodeBytes(r.Bytes(), &items)
+	return items, err
+}
+
+// Append encodes v and adds it to the end of the list.
+func (r *RawList[T]) Append(v T) error {
+	elem, err := EncodeToBytes(v)
+	if err != nil {
+		return err
+	}
+	content := r.Content()
+	size := uint64(len(content) + len(elem))
+	enc := make([]byte, ListSize(size))
+	n := puthead(enc, 0xC0, 0xF7, size)
+	n += copy(enc[n:], content)
+	copy(enc[n:], elem)
+	r.enc = enc
+	return nil
+}
+
+// EncodeRLP implements Encoder.
+func (r RawList[T]) EncodeRLP(w io.Writer) error {
+	_, err := w.Write(r.Bytes())
+	return err
+}
+
+// DecodeRLP implements Decoder. The content of the list is stored without
+// decoding its elements, but their headers are checked.
+func (r *RawList[T]) DecodeRLP(s *Stream) error {
+	k, _, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if k != List {
+		return ErrExpectedList
+	}
+	enc, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	content, _, err := SplitList(enc)
+	if err != nil {
+		return err
+	}
+	if _, err := CountValues(content); err != nil {
+		return err
+	}
+	r.enc = enc
+	return nil
+}
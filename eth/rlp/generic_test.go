@@ -0,0 +1,115 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeAs(t *testing.T) {
+	v, err := DecodeAs[simplestruct](unhex("C50583343434"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (simplestruct{A: 5, B: "444"}); v != want {
+		t.Errorf("got %+v, want %+v", v, want)
+	}
+	if _, err := DecodeAs[uint](unhex("C0")); err == nil {
+		t.Error("expected error decoding list into uint")
+	}
+	if _, err := DecodeAs[uint](unhex("0102")); err != ErrMoreThanOneValue {
+		t.Errorf("wrong error for trailing data: %v", err)
+	}
+}
+
+func TestEncodeSlice(t *testing.T) {
+	enc, err := EncodeSlice([]uint{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, unhex("C3010203")) {
+		t.Errorf("wrong encoding %x", enc)
+	}
+	if enc, _ = EncodeSlice[string](nil); !bytes.Equal(enc, EmptyList) {
+		t.Errorf("wrong encoding of nil slice %x", enc)
+	}
+}
+
+func TestRawList(t *testing.T) {
+	var l RawList[simplestruct]
+	if l.Len() != 0 || !bytes.Equal(l.Bytes(), EmptyList) {
+		t.Fatalf("zero value is not an empty list: %x", l.Bytes())
+	}
+	items := []simplestruct{{1, "a"}, {2, "bb"}, {3, "ccc"}}
+	for _, item := range items {
+		if err := l.Append(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if l.Len() != 3 {
+		t.Errorf("wrong length %d", l.Len())
+	}
+	if v, err := l.Get(1); err != nil || v != items[1] {
+		t.Errorf("Get(1) = %+v, %v", v, err)
+	}
+	if _, err := l.Get(3); err != errIndexRange {
+		t.Errorf("wrong error for index out of range: %v", err)
+	}
+	all, err := l.Items()
+	if err != nil || !reflect.DeepEqual(all, items) {
+		t.Errorf("Items() = %+v, %v", all, err)
+	}
+
+	// Encoding must match the plain slice.
+	want, _ := EncodeToBytes(items)
+	l2, err := NewRawList(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(l.Bytes(), want) || !bytes.Equal(l2.Bytes(), want) {
+		t.Errorf("wrong encoding %x, want %x", l.Bytes(), want)
+	}
+
+	// Roundtrip as a struct field.
+	type withList struct {
+		N    uint
+		List RawList[simplestruct]
+	}
+	enc, err := EncodeToBytes(&withList{N: 7, List: l})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := DecodeAs[withList](enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.N != 7 || !bytes.Equal(dec.List.Bytes(), want) {
+		t.Errorf("wrong decoded value %+v", dec)
+	}
+	// Structs holding a RawList and bare lists encode by value too.
+	if byValue, err := EncodeToBytes(withList{N: 7, List: l}); err != nil || !bytes.Equal(byValue, enc) {
+		t.Errorf("struct by value: got %x, %v, want %x", byValue, err, enc)
+	}
+	if byValue, err := EncodeToBytes(l); err != nil || !bytes.Equal(byValue, want) {
+		t.Errorf("list by value: got %x, %v, want %x", byValue, err, want)
+	}
+	if _, err := DecodeAs[withList](unhex("C20780")); err != ErrExpectedList {
+		t.Errorf("wrong error for string input: %v", err)
+	}
+}
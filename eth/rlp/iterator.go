@@ -0,0 +1,100 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import "errors"
+
+var errNoElement = errors.New("rlp: iterator is not positioned at an element")
+
+// Iterator walks the elements of an encoded RLP list. It does not copy or
+// decode the input, all values it returns are subslices of the input.
+type Iterator struct {
+	data    []byte // input remaining after the current element
+	kind    Kind
+	value   []byte // encoding of the current element
+	content []byte // content of the current element
+	err     error
+}
+
+// NewListIterator creates an iterator over the elements of the encoded
+// list b. The input must contain exactly one list.
+func NewListIterator(b []byte) (*Iterator, error) {
+	content, rest, err := SplitList(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, ErrMoreThanOneValue
+	}
+	return &Iterator{data: content}, nil
+}
+
+// Next advances to the next element. It returns false when there are no
+// more elements or the input is malformed, in which case Err returns the
+// error.
+func (it *Iterator) Next() bool {
+	if len(it.data) == 0 || it.err != nil {
+		it.value, it.content = nil, nil
+		return false
+	}
+	k, tagsize, size, err := readKind(it.data)
+	if err != nil {
+		it.err = err
+		it.value, it.content = nil, nil
+		return false
+	}
+	end := tagsize + size
+	it.kind = k
+	it.value = it.data[:end:end]
+	it.content = it.data[tagsize:end:end]
+	it.data = it.data[end:]
+	return true
+}
+
+// Kind returns the kind of the current element.
+func (it *Iterator) Kind() Kind {
+	return it.kind
+}
+
+// Value returns the encoding of the current element, including its
+// type tag.
+func (it *Iterator) Value() RawValue {
+	return it.value
+}
+
+// Content returns the content of the current element without the
+// type tag. For Byte elements, this is the byte itself.
+func (it *Iterator) Content() []byte {
+	return it.content
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// List returns an iterator over the elements of the current element,
+// which must be a list.
+func (it *Iterator) List() (*Iterator, error) {
+	if it.value == nil {
+		return nil, errNoElement
+	}
+	if it.kind != List {
+		return nil, ErrExpectedList
+	}
+	return &Iterator{data: it.content}, nil
+}
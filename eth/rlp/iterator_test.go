@@ -0,0 +1,128 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	input := unhex("C9 01 80 C3 02 8103 820404")
+	it, err := NewListIterator(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		kind    Kind
+		value   string
+		content string
+	}{
+		{Byte, "01", "01"},
+		{String, "80", ""},
+		{List, "C3028103", "028103"},
+		{String, "820404", "0404"},
+	}
+	for i, w := range want {
+		if !it.Next() {
+			t.Fatalf("element %d: Next returned false, err %v", i, it.Err())
+		}
+		if it.Kind() != w.kind {
+			t.Errorf("element %d: kind %v, want %v", i, it.Kind(), w.kind)
+		}
+		if !bytes.Equal(it.Value(), unhex(w.value)) {
+			t.Errorf("element %d: value %x, want %s", i, it.Value(), w.value)
+		}
+		if !bytes.Equal(it.Content(), unhex(w.content)) {
+			t.Errorf("element %d: content %x, want %s", i, it.Content(), w.content)
+		}
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after last element")
+	}
+	if it.Err() != nil {
+		t.Fatalf("unexpected error: %v", it.Err())
+	}
+}
+
+func TestIteratorNested(t *testing.T) {
+	input := unhex("C6 C3 01 02 03 C0 04")
+	it, _ := NewListIterator(input)
+	it.Next()
+	inner, err := it.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []byte
+	for inner.Next() {
+		got = append(got, inner.Content()...)
+	}
+	if !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("nested elements %x, want 010203", got)
+	}
+	// The values must alias the input.
+	it.Value()[1] = 0x05
+	if input[2] != 0x05 {
+		t.Error("iterator value does not share memory with the input")
+	}
+	it.Next()
+	if inner, err := it.List(); err != nil || inner.Next() {
+		t.Errorf("empty list: err %v", err)
+	}
+	it.Next()
+	if _, err := it.List(); err != ErrExpectedList {
+		t.Errorf("List on string element: got %v, want %v", err, ErrExpectedList)
+	}
+}
+
+func TestIteratorErrors(t *testing.T) {
+	if _, err := NewListIterator(unhex("01")); err != ErrExpectedList {
+		t.Errorf("non-list input: got %v, want %v", err, ErrExpectedList)
+	}
+	if _, err := NewListIterator(unhex("C0 C0")); err != ErrMoreThanOneValue {
+		t.Errorf("trailing data: got %v, want %v", err, ErrMoreThanOneValue)
+	}
+	it, err := NewListIterator(unhex("C4 01 8142 02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 1 || it.Err() != ErrCanonSize {
+		t.Errorf("got %d elements and error %v, want 1 and %v", n, it.Err(), ErrCanonSize)
+	}
+	if _, err := it.List(); err != errNoElement {
+		t.Errorf("List after end: got %v, want %v", err, errNoElement)
+	}
+}
+
+func BenchmarkIterator(b *testing.B) {
+	list := make([]interface{}, 1000)
+	for i := range list {
+		list[i] = []interface{}{uint(i), bytes.Repeat([]byte{1}, 32)}
+	}
+	input, _ := EncodeToBytes(list)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it, _ := NewListIterator(input)
+		for it.Next() {
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// rlpdump prints the structure of arbitrary RLP data.
+//
+// Usage:
+//
+//	rlpdump [-json] [-bin] [-in file] [hex]
+//
+// The input is read from the hex argument, the file given by -in or
+// standard input, in that order. It is hex-encoded unless -bin is set.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+func main() {
+	var (
+		infile  = flag.String("in", "", "input file (default stdin)")
+		binary  = flag.Bool("bin", false, "input is raw binary instead of hex")
+		jsonOut = flag.Bool("json", false, "print JSON instead of a tree")
+	)
+	flag.Parse()
+
+	input, err := readInput(flag.Arg(0), *infile, *binary)
+	if err != nil {
+		fatal(err)
+	}
+	if *jsonOut {
+		out, err := rlp.DumpJSON(input)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(string(out))
+		return
+	}
+	if err := rlp.Dump(os.Stdout, input); err != nil {
+		fatal(err)
+	}
+}
+
+func readInput(arg, file string, binary bool) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case arg != "":
+		data = []byte(arg)
+	case file != "":
+		data, err = ioutil.ReadFile(file)
+	default:
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil || binary {
+		return data, err
+	}
+	s := strings.TrimPrefix(string(bytes.TrimSpace(data)), "0x")
+	s = strings.Join(strings.Fields(s), "")
+	return hex.DecodeString(s)
+}
+
+func fatal(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, append([]interface{}{"rlpdump:"}, args...)...)
+	os.Exit(1)
+}
@@ -0,0 +1,682 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const rlpPackagePath = "github.com/arcology-network/3rd-party/eth/rlp"
+
+// Config describes a single rlpgen run.
+type Config struct {
+	Dir     string // directory of the package containing the type
+	Type    string // name of the struct type
+	Exclude string // file left out when loading the package, usually the previous output
+	Encoder bool   // generate EncodeRLP
+	Decoder bool   // generate DecodeRLP
+}
+
+// Process loads the package and returns the formatted source of the
+// generated methods.
+func (cfg *Config) Process() ([]byte, error) {
+	pkg, err := loadPackage(cfg.Dir, cfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := pkg.Scope().Lookup(cfg.Type).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", cfg.Type, cfg.Dir)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a named type", cfg.Type)
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("%s is not a struct type", cfg.Type)
+	}
+
+	ctx := newGenContext(pkg)
+	var body bytes.Buffer
+	if cfg.Encoder {
+		if err := ctx.genEncodeMethod(&body, named); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Decoder {
+		if err := ctx.genDecodeMethod(&body, named); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by rlpgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg.Name())
+	fmt.Fprintf(&out, "import (\n")
+	var std, other []string
+	for path := range ctx.imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, path := range std {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		fmt.Fprintf(&out, "\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	out.Write(body.Bytes())
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can't format generated code: %v\n%s", err, out.Bytes())
+	}
+	return code, nil
+}
+
+// loadPackage parses and type-checks the package in dir, leaving out the
+// exclude file.
+func loadPackage(dir, exclude string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exclude != "" {
+		if exclude, err = filepath.Abs(exclude); err != nil {
+			return nil, err
+		}
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		path, err := filepath.Abs(filepath.Join(bp.Dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if path == exclude {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	// Type errors are tolerated because other generated files of the
+	// package may refer to methods of the excluded file.
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(bp.ImportPath, fset, files, nil)
+	return pkg, nil
+}
+
+// rlpTags mirrors the struct tags understood by package rlp.
+type rlpTags struct {
+	nilOK    bool
+	tail     bool
+	optional bool
+	ignored  bool
+}
+
+func parseTags(st *types.Struct, fi int) (rlpTags, error) {
+	f := st.Field(fi)
+	var ts rlpTags
+	tag := reflect.StructTag(st.Tag(fi)).Get("rlp")
+	for _, t := range strings.Split(tag, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "":
+		case "-":
+			ts.ignored = true
+		case "nil":
+			ts.nilOK = true
+		case "tail":
+			ts.tail = true
+			if fi != st.NumFields()-1 {
+				return ts, fmt.Errorf(`invalid struct tag "tail" for %s (must be on last field)`, f.Name())
+			}
+			if _, ok := f.Type().Underlying().(*types.Slice); !ok {
+				return ts, fmt.Errorf(`invalid struct tag "tail" for %s (field type is not slice)`, f.Name())
+			}
+		case "optional":
+			ts.optional = true
+		default:
+			return ts, fmt.Errorf("unknown struct tag %q on %s", t, f.Name())
+		}
+	}
+	if ts.optional && ts.tail {
+		return ts, fmt.Errorf(`invalid struct tag "optional" for %s (also has "tail" tag)`, f.Name())
+	}
+	return ts, nil
+}
+
+type structField struct {
+	name string
+	typ  types.Type
+	tags rlpTags
+}
+
+// structFields returns the encoded fields of st, following structFields in
+// package rlp.
+func structFields(st *types.Struct) ([]structField, error) {
+	var (
+		fields       []structField
+		lastOptional string
+	)
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Exported() {
+			continue
+		}
+		ts, err := parseTags(st, i)
+		if err != nil {
+			return nil, err
+		}
+		if ts.ignored {
+			continue
+		}
+		if ts.optional {
+			lastOptional = f.Name()
+		} else if lastOptional != "" {
+			return nil, fmt.Errorf(`invalid struct tag "" for %s (must be optional because preceding field %q is optional)`, f.Name(), lastOptional)
+		}
+		fields = append(fields, structField{f.Name(), f.Type(), ts})
+	}
+	return fields, nil
+}
+
+type genContext struct {
+	pkg     *types.Package
+	imports map[string]bool
+	tmp     int
+	inline  map[types.Type]bool // named struct types currently being inlined
+}
+
+func newGenContext(pkg *types.Package) *genContext {
+	return &genContext{
+		pkg:     pkg,
+		imports: map[string]bool{rlpPackagePath: true},
+		inline:  make(map[types.Type]bool),
+	}
+}
+
+func (ctx *genContext) qualifier(p *types.Package) string {
+	if p == ctx.pkg {
+		return ""
+	}
+	ctx.imports[p.Path()] = true
+	return p.Name()
+}
+
+func (ctx *genContext) typeString(t types.Type) string {
+	return types.TypeString(t, ctx.qualifier)
+}
+
+// temp returns a fresh temporary variable name.
+func (ctx *genContext) temp() string {
+	v := fmt.Sprintf("_tmp%d", ctx.tmp)
+	ctx.tmp++
+	return v
+}
+
+// convert returns v converted to basic if t is not already identical to it.
+func convert(t types.Type, basic types.BasicKind, v string) string {
+	if types.Identical(t, types.Typ[basic]) {
+		return v
+	}
+	return fmt.Sprintf("%s(%s)", types.Typ[basic].Name(), v)
+}
+
+func (ctx *genContext) genEncodeMethod(b *bytes.Buffer, named *types.Named) error {
+	ctx.imports["io"] = true
+	ctx.inline[named] = true
+	defer delete(ctx.inline, named)
+
+	name := named.Obj().Name()
+	fmt.Fprintf(b, "// EncodeRLP implements rlp.Encoder.\n")
+	fmt.Fprintf(b, "func (obj *%s) EncodeRLP(_w io.Writer) error {\n", name)
+	fmt.Fprintf(b, "if obj == nil {\n_, err := _w.Write(rlp.EmptyList)\nreturn err\n}\n")
+	fmt.Fprintf(b, "w := rlp.NewEncoderBuffer(_w)\n")
+	if err := ctx.genWriteStruct(b, named.Underlying().(*types.Struct), "obj"); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	fmt.Fprintf(b, "return w.Flush()\n}\n\n")
+	return nil
+}
+
+func (ctx *genContext) genDecodeMethod(b *bytes.Buffer, named *types.Named) error {
+	ctx.inline[named] = true
+	defer delete(ctx.inline, named)
+
+	name := named.Obj().Name()
+	v := ctx.temp()
+	fmt.Fprintf(b, "// DecodeRLP implements rlp.Decoder.\n")
+	fmt.Fprintf(b, "func (obj *%s) DecodeRLP(dec *rlp.Stream) error {\n", name)
+	fmt.Fprintf(b, "var %s %s\n", v, name)
+	if err := ctx.genDecodeStruct(b, named.Underlying().(*types.Struct), v); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	fmt.Fprintf(b, "*obj = %s\nreturn nil\n}\n\n", v)
+	return nil
+}
+
+// genWrite emits code encoding the addressable expression v of type t.
+// The cases follow makeWriter in package rlp.
+func (ctx *genContext) genWrite(b *bytes.Buffer, t types.Type, v string, ts rlpTags) error {
+	switch {
+	case isRawValue(t):
+		fmt.Fprintf(b, "w.Write(%s)\n", v)
+		return nil
+	case isInterface(t):
+		return ctx.genWriteFallback(b, v)
+	case hasMethod(t, "EncodeRLP") || !isPointer(t) && hasMethod(types.NewPointer(t), "EncodeRLP"):
+		fmt.Fprintf(b, "if err := %s.EncodeRLP(&w); err != nil {\nreturn err\n}\n", v)
+		return nil
+	case isBigInt(t):
+		fmt.Fprintf(b, "if %s.Sign() == -1 {\nreturn rlp.ErrNegativeBigInt\n}\n", v)
+		fmt.Fprintf(b, "w.WriteBigInt(&%s)\n", v)
+		return nil
+	case isPointer(t) && isBigInt(t.Underlying().(*types.Pointer).Elem()):
+		fmt.Fprintf(b, "if %s == nil {\nw.Write(rlp.EmptyString)\n} else {\n", v)
+		fmt.Fprintf(b, "if %s.Sign() == -1 {\nreturn rlp.ErrNegativeBigInt\n}\n", v)
+		fmt.Fprintf(b, "w.WriteBigInt(%s)\n}\n", v)
+		return nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsUnsigned != 0:
+			fmt.Fprintf(b, "w.WriteUint64(%s)\n", convert(t, types.Uint64, v))
+		case u.Info()&types.IsInteger != 0:
+			return ctx.genWriteFallback(b, v)
+		case u.Kind() == types.Bool:
+			fmt.Fprintf(b, "w.WriteBool(%s)\n", convert(t, types.Bool, v))
+		case u.Kind() == types.String:
+			fmt.Fprintf(b, "w.WriteString(%s)\n", convert(t, types.String, v))
+		default:
+			return fmt.Errorf("type %s is not RLP-serializable", t)
+		}
+	case *types.Slice:
+		if isByte(u.Elem()) {
+			if types.Identical(t, types.NewSlice(types.Typ[types.Byte])) {
+				fmt.Fprintf(b, "w.WriteBytes(%s)\n", v)
+			} else {
+				fmt.Fprintf(b, "w.WriteBytes([]byte(%s))\n", v)
+			}
+			return nil
+		}
+		return ctx.genWriteList(b, u.Elem(), v, ts.tail)
+	case *types.Array:
+		if isByte(u.Elem()) {
+			fmt.Fprintf(b, "w.WriteBytes(%s[:])\n", v)
+			return nil
+		}
+		return ctx.genWriteList(b, u.Elem(), v, false)
+	case *types.Struct:
+		if ctx.inline[t] {
+			// Recursive type, let package rlp deal with it.
+			return ctx.genWriteFallback(b, v)
+		}
+		ctx.inline[t] = true
+		defer delete(ctx.inline, t)
+		return ctx.genWriteStruct(b, u, v)
+	case *types.Pointer:
+		return ctx.genWritePtr(b, u.Elem(), v)
+	default:
+		return fmt.Errorf("type %s is not RLP-serializable", t)
+	}
+	return nil
+}
+
+// genWriteFallback emits a call to the reflection-based encoder.
+func (ctx *genContext) genWriteFallback(b *bytes.Buffer, v string) error {
+	fmt.Fprintf(b, "if err := rlp.Encode(&w, &%s); err != nil {\nreturn err\n}\n", v)
+	return nil
+}
+
+func (ctx *genContext) genWriteList(b *bytes.Buffer, elem types.Type, v string, tail bool) error {
+	var list string
+	if !tail {
+		list = ctx.temp()
+		fmt.Fprintf(b, "%s := w.List()\n", list)
+	}
+	i := ctx.temp()
+	fmt.Fprintf(b, "for %s := range %s {\n", i, v)
+	if err := ctx.genWrite(b, elem, fmt.Sprintf("%s[%s]", v, i), rlpTags{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "}\n")
+	if !tail {
+		fmt.Fprintf(b, "w.ListEnd(%s)\n", list)
+	}
+	return nil
+}
+
+func (ctx *genContext) genWriteStruct(b *bytes.Buffer, st *types.Struct, v string) error {
+	fields, err := structFields(st)
+	if err != nil {
+		return err
+	}
+	// Trailing optional fields are omitted while they are zero, so an
+	// optional field is written if it or any later field is non-zero.
+	var nonZero []string
+	for _, f := range fields {
+		if f.tags.optional {
+			cond := ctx.temp()
+			fmt.Fprintf(b, "%s := %s\n", cond, ctx.nonZero(f.typ, v+"."+f.name))
+			nonZero = append(nonZero, cond)
+		}
+	}
+	list := ctx.temp()
+	fmt.Fprintf(b, "%s := w.List()\n", list)
+	for i, f := range fields {
+		if f.tags.optional {
+			fmt.Fprintf(b, "if %s {\n", strings.Join(nonZero, " || "))
+			nonZero = nonZero[1:]
+		}
+		if err := ctx.genWrite(b, f.typ, v+"."+f.name, f.tags); err != nil {
+			return fmt.Errorf("field %s: %v", fields[i].name, err)
+		}
+		if f.tags.optional {
+			fmt.Fprintf(b, "}\n")
+		}
+	}
+	fmt.Fprintf(b, "w.ListEnd(%s)\n", list)
+	return nil
+}
+
+// nonZero returns an expression reporting whether v of type t is not the
+// zero value, with the semantics of reflect.Value.IsZero.
+func (ctx *genContext) nonZero(t types.Type, v string) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return v
+		case u.Info()&types.IsString != 0:
+			return v + ` != ""`
+		default:
+			return v + " != 0"
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Chan, *types.Signature:
+		return v + " != nil"
+	}
+	if types.Comparable(t) {
+		return fmt.Sprintf("%s != (%s{})", v, ctx.typeString(t))
+	}
+	ctx.imports["reflect"] = true
+	return fmt.Sprintf("!reflect.ValueOf(&%s).Elem().IsZero()", v)
+}
+
+// genWritePtr encodes a pointer. Nil pointers are encoded like makePtrWriter
+// in package rlp does.
+func (ctx *genContext) genWritePtr(b *bytes.Buffer, elem types.Type, v string) error {
+	fmt.Fprintf(b, "if %s == nil {\n", v)
+	switch u := elem.Underlying().(type) {
+	case *types.Array:
+		if isByte(u.Elem()) {
+			fmt.Fprintf(b, "w.Write(rlp.EmptyString)\n")
+		} else {
+			fmt.Fprintf(b, "w.Write(rlp.EmptyList)\n")
+		}
+	case *types.Struct:
+		fmt.Fprintf(b, "w.Write(rlp.EmptyList)\n")
+	default:
+		zero := ctx.temp()
+		fmt.Fprintf(b, "var %s %s\n", zero, ctx.typeString(elem))
+		if err := ctx.genWrite(b, elem, zero, rlpTags{}); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(b, "} else {\n")
+	if err := ctx.genWrite(b, elem, "(*"+v+")", rlpTags{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "}\n")
+	return nil
+}
+
+// genDecode emits code decoding into the addressable expression v of type t.
+// The cases follow makeDecoder in package rlp.
+func (ctx *genContext) genDecode(b *bytes.Buffer, t types.Type, v string, ts rlpTags) error {
+	switch {
+	case isRawValue(t):
+		return ctx.genDecodeCall(b, v, "dec.Raw()", "%s")
+	case isInterface(t) || hasMethod(t, "DecodeRLP"):
+		return ctx.genDecodeFallback(b, v)
+	case !isPointer(t) && hasMethod(types.NewPointer(t), "DecodeRLP"):
+		fmt.Fprintf(b, "if err := %s.DecodeRLP(dec); err != nil {\nreturn err\n}\n", v)
+		return nil
+	case isBigInt(t):
+		return ctx.genDecodeCall(b, v, "dec.BigInt()", "")
+	case isPointer(t) && isBigInt(t.Underlying().(*types.Pointer).Elem()):
+		return ctx.genDecodeCall(b, v, "dec.BigInt()", "%s")
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		conv := "%s"
+		if !types.Identical(t, types.Typ[u.Kind()]) {
+			conv = ctx.typeString(t) + "(%s)"
+		}
+		switch u.Kind() {
+		case types.Uint8:
+			return ctx.genDecodeCall(b, v, "dec.Uint8()", conv)
+		case types.Uint16:
+			return ctx.genDecodeCall(b, v, "dec.Uint16()", conv)
+		case types.Uint32:
+			return ctx.genDecodeCall(b, v, "dec.Uint32()", conv)
+		case types.Uint64:
+			return ctx.genDecodeCall(b, v, "dec.Uint64()", conv)
+		case types.Uint, types.Uintptr:
+			return ctx.genDecodeCall(b, v, "dec.Uint64()", ctx.typeString(t)+"(%s)")
+		case types.Bool:
+			return ctx.genDecodeCall(b, v, "dec.Bool()", conv)
+		case types.String:
+			return ctx.genDecodeCall(b, v, "dec.Bytes()", ctx.typeString(t)+"(%s)")
+		}
+		if u.Info()&types.IsInteger != 0 {
+			return ctx.genDecodeFallback(b, v)
+		}
+		return fmt.Errorf("type %s is not RLP-serializable", t)
+	case *types.Slice:
+		if isDecodeByte(u.Elem()) {
+			conv := "%s"
+			if !types.Identical(t, types.NewSlice(types.Typ[types.Byte])) {
+				conv = ctx.typeString(t) + "(%s)"
+			}
+			return ctx.genDecodeCall(b, v, "dec.Bytes()", conv)
+		}
+		return ctx.genDecodeSlice(b, t, u.Elem(), v, ts.tail)
+	case *types.Array:
+		if isDecodeByte(u.Elem()) {
+			fmt.Fprintf(b, "if err := dec.ReadBytes(%s[:]); err != nil {\nreturn err\n}\n", v)
+			return nil
+		}
+		fmt.Fprintf(b, "if _, err := dec.List(); err != nil {\nreturn err\n}\n")
+		i := ctx.temp()
+		fmt.Fprintf(b, "for %s := range %s {\n", i, v)
+		if err := ctx.genDecode(b, u.Elem(), fmt.Sprintf("%s[%s]", v, i), rlpTags{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "}\n")
+		fmt.Fprintf(b, "if err := dec.ListEnd(); err != nil {\nreturn err\n}\n")
+		return nil
+	case *types.Struct:
+		if ctx.inline[t] {
+			return ctx.genDecodeFallback(b, v)
+		}
+		ctx.inline[t] = true
+		defer delete(ctx.inline, t)
+		return ctx.genDecodeStruct(b, u, v)
+	case *types.Pointer:
+		return ctx.genDecodePtr(b, u.Elem(), v, ts.nilOK)
+	default:
+		return fmt.Errorf("type %s is not RLP-serializable", t)
+	}
+}
+
+// genDecodeCall emits a call of a value-returning Stream method and assigns
+// the result to v, wrapped in conv.
+func (ctx *genContext) genDecodeCall(b *bytes.Buffer, v, call, conv string) error {
+	tmp := ctx.temp()
+	fmt.Fprintf(b, "%s, err := %s\n", tmp, call)
+	fmt.Fprintf(b, "if err != nil {\nreturn err\n}\n")
+	if conv == "" {
+		// big.Int values are set in place.
+		fmt.Fprintf(b, "%s.Set(%s)\n", v, tmp)
+	} else {
+		fmt.Fprintf(b, "%s = %s\n", v, fmt.Sprintf(conv, tmp))
+	}
+	return nil
+}
+
+// genDecodeFallback emits a call to the reflection-based decoder.
+func (ctx *genContext) genDecodeFallback(b *bytes.Buffer, v string) error {
+	fmt.Fprintf(b, "if err := dec.Decode(&%s); err != nil {\nreturn err\n}\n", v)
+	return nil
+}
+
+func (ctx *genContext) genDecodeSlice(b *bytes.Buffer, t, elem types.Type, v string, tail bool) error {
+	if !tail {
+		fmt.Fprintf(b, "if _, err := dec.List(); err != nil {\nreturn err\n}\n")
+	}
+	slice, e := ctx.temp(), ctx.temp()
+	fmt.Fprintf(b, "%s := %s{}\n", slice, ctx.typeString(t))
+	fmt.Fprintf(b, "for dec.MoreDataInList() {\n")
+	fmt.Fprintf(b, "var %s %s\n", e, ctx.typeString(elem))
+	if err := ctx.genDecode(b, elem, e, rlpTags{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "%s = append(%s, %s)\n}\n", slice, slice, e)
+	if !tail {
+		fmt.Fprintf(b, "if err := dec.ListEnd(); err != nil {\nreturn err\n}\n")
+	}
+	fmt.Fprintf(b, "%s = %s\n", v, slice)
+	return nil
+}
+
+// genDecodeStruct decodes into v, which must hold the zero value so that
+// missing optional fields are left zero.
+func (ctx *genContext) genDecodeStruct(b *bytes.Buffer, st *types.Struct, v string) error {
+	fields, err := structFields(st)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "if _, err := dec.List(); err != nil {\nreturn err\n}\n")
+	for _, f := range fields {
+		if f.tags.optional {
+			fmt.Fprintf(b, "if dec.MoreDataInList() {\n")
+		}
+		if err := ctx.genDecode(b, f.typ, v+"."+f.name, f.tags); err != nil {
+			return fmt.Errorf("field %s: %v", f.name, err)
+		}
+		if f.tags.optional {
+			fmt.Fprintf(b, "}\n")
+		}
+	}
+	fmt.Fprintf(b, "if err := dec.ListEnd(); err != nil {\nreturn err\n}\n")
+	return nil
+}
+
+// genDecodePtr decodes into a new value of the pointer's element type. With
+// the "nil" tag, empty input values decode as a nil pointer.
+func (ctx *genContext) genDecodePtr(b *bytes.Buffer, elem types.Type, v string, nilOK bool) error {
+	if nilOK {
+		kind, size := ctx.temp(), ctx.temp()
+		fmt.Fprintf(b, "%s, %s, err := dec.Kind()\n", kind, size)
+		fmt.Fprintf(b, "if err != nil {\nreturn err\n}\n")
+		fmt.Fprintf(b, "if %s == 0 && %s != rlp.Byte {\n", size, kind)
+		fmt.Fprintf(b, "if _, err := dec.Raw(); err != nil {\nreturn err\n}\n")
+		fmt.Fprintf(b, "%s = nil\n} else {\n", v)
+	}
+	e := ctx.temp()
+	fmt.Fprintf(b, "var %s %s\n", e, ctx.typeString(elem))
+	if err := ctx.genDecode(b, elem, e, rlpTags{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "%s = &%s\n", v, e)
+	if nilOK {
+		fmt.Fprintf(b, "}\n")
+	}
+	return nil
+}
+
+func hasMethod(t types.Type, name string) bool {
+	return types.NewMethodSet(t).Lookup(nil, name) != nil
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Pointer)
+	return ok
+}
+
+func isInterface(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Interface)
+	return ok
+}
+
+func isNamed(t types.Type, path, name string) bool {
+	n, ok := t.(*types.Named)
+	if !ok || n.Obj().Pkg() == nil {
+		return false
+	}
+	return n.Obj().Pkg().Path() == path && n.Obj().Name() == name
+}
+
+func isBigInt(t types.Type) bool {
+	return isNamed(t, "math/big", "Int")
+}
+
+func isRawValue(t types.Type) bool {
+	return isNamed(t, rlpPackagePath, "RawValue")
+}
+
+// isByte reports whether t is encoded as a byte of an RLP string when it is
+// the element type of a slice or array.
+func isByte(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Uint8 && !hasMethod(t, "EncodeRLP")
+}
+
+// isDecodeByte is the decoding counterpart of isByte.
+func isDecodeByte(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Uint8 && !hasMethod(types.NewPointer(t), "DecodeRLP")
+}
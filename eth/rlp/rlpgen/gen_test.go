@@ -0,0 +1,64 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerated checks that the committed code in internal/gentest is
+// what rlpgen currently generates. Run go generate in that directory to
+// update it.
+func TestGenerated(t *testing.T) {
+	dir := filepath.Join("internal", "gentest")
+	for _, typ := range []string{"Inner", "Basic", "Lists", "Pointers", "Optional"} {
+		file := filepath.Join(dir, "gen_"+strings.ToLower(typ)+"_rlp.go")
+		cfg := Config{Dir: dir, Type: typ, Exclude: file, Encoder: true, Decoder: true}
+		code, err := cfg.Process()
+		if err != nil {
+			t.Fatalf("%s: %v", typ, err)
+		}
+		want, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(code, want) {
+			t.Errorf("%s: generated code differs from %s", typ, file)
+		}
+	}
+}
+
+func TestProcessErrors(t *testing.T) {
+	dir := filepath.Join("internal", "gentest")
+	tests := []struct {
+		typ string
+		err string
+	}{
+		{"Missing", "type Missing not found in " + dir},
+		{"namedUint", "namedUint is not a struct type"},
+	}
+	for _, tt := range tests {
+		cfg := Config{Dir: dir, Type: tt.typ, Encoder: true, Decoder: true}
+		if _, err := cfg.Process(); err == nil || err.Error() != tt.err {
+			t.Errorf("%s: error mismatch: got %v, want %q", tt.typ, err, tt.err)
+		}
+	}
+}
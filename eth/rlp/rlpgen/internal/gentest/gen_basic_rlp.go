@@ -0,0 +1,136 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"io"
+
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// EncodeRLP implements rlp.Encoder.
+func (obj *Basic) EncodeRLP(_w io.Writer) error {
+	if obj == nil {
+		_, err := _w.Write(rlp.EmptyList)
+		return err
+	}
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	w.WriteUint64(uint64(obj.Uint8))
+	w.WriteUint64(uint64(obj.Uint16))
+	w.WriteUint64(uint64(obj.Uint32))
+	w.WriteUint64(obj.Uint64)
+	w.WriteUint64(uint64(obj.Uint))
+	w.WriteBool(obj.Bool)
+	w.WriteString(obj.String)
+	w.WriteBytes(obj.Bytes)
+	w.WriteBytes(obj.Array[:])
+	w.WriteBytes(obj.Hash[:])
+	if obj.Big == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		if obj.Big.Sign() == -1 {
+			return rlp.ErrNegativeBigInt
+		}
+		w.WriteBigInt(obj.Big)
+	}
+	if obj.BigVal.Sign() == -1 {
+		return rlp.ErrNegativeBigInt
+	}
+	w.WriteBigInt(&obj.BigVal)
+	w.WriteUint64(uint64(obj.Named))
+	w.WriteBytes([]byte(obj.NamedB))
+	w.Write(obj.Raw)
+	if err := rlp.Encode(&w, &obj.Int); err != nil {
+		return err
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (obj *Basic) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp1 Basic
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp2, err := dec.Uint8()
+	if err != nil {
+		return err
+	}
+	_tmp1.Uint8 = _tmp2
+	_tmp3, err := dec.Uint16()
+	if err != nil {
+		return err
+	}
+	_tmp1.Uint16 = _tmp3
+	_tmp4, err := dec.Uint32()
+	if err != nil {
+		return err
+	}
+	_tmp1.Uint32 = _tmp4
+	_tmp5, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	_tmp1.Uint64 = _tmp5
+	_tmp6, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	_tmp1.Uint = uint(_tmp6)
+	_tmp7, err := dec.Bool()
+	if err != nil {
+		return err
+	}
+	_tmp1.Bool = _tmp7
+	_tmp8, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	_tmp1.String = string(_tmp8)
+	_tmp9, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	_tmp1.Bytes = _tmp9
+	if err := dec.ReadBytes(_tmp1.Array[:]); err != nil {
+		return err
+	}
+	if err := dec.ReadBytes(_tmp1.Hash[:]); err != nil {
+		return err
+	}
+	_tmp10, err := dec.BigInt()
+	if err != nil {
+		return err
+	}
+	_tmp1.Big = _tmp10
+	_tmp11, err := dec.BigInt()
+	if err != nil {
+		return err
+	}
+	_tmp1.BigVal.Set(_tmp11)
+	_tmp12, err := dec.Uint32()
+	if err != nil {
+		return err
+	}
+	_tmp1.Named = namedUint(_tmp12)
+	_tmp13, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	_tmp1.NamedB = namedBytes(_tmp13)
+	_tmp14, err := dec.Raw()
+	if err != nil {
+		return err
+	}
+	_tmp1.Raw = _tmp14
+	if err := dec.Decode(&_tmp1.Int); err != nil {
+		return err
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	*obj = _tmp1
+	return nil
+}
@@ -0,0 +1,46 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"io"
+
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// EncodeRLP implements rlp.Encoder.
+func (obj *Inner) EncodeRLP(_w io.Writer) error {
+	if obj == nil {
+		_, err := _w.Write(rlp.EmptyList)
+		return err
+	}
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	w.WriteUint64(obj.A)
+	w.WriteBytes(obj.B)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (obj *Inner) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp1 Inner
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp2, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	_tmp1.A = _tmp2
+	_tmp3, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	_tmp1.B = _tmp3
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	*obj = _tmp1
+	return nil
+}
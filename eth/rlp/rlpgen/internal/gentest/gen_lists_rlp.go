@@ -0,0 +1,205 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"io"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// EncodeRLP implements rlp.Encoder.
+func (obj *Lists) EncodeRLP(_w io.Writer) error {
+	if obj == nil {
+		_, err := _w.Write(rlp.EmptyList)
+		return err
+	}
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	_tmp1 := w.List()
+	for _tmp2 := range obj.Uints {
+		w.WriteUint64(obj.Uints[_tmp2])
+	}
+	w.ListEnd(_tmp1)
+	_tmp3 := w.List()
+	for _tmp4 := range obj.Strings {
+		w.WriteString(obj.Strings[_tmp4])
+	}
+	w.ListEnd(_tmp3)
+	_tmp5 := w.List()
+	for _tmp6 := range obj.Nested {
+		w.WriteBytes(obj.Nested[_tmp6])
+	}
+	w.ListEnd(_tmp5)
+	_tmp7 := w.List()
+	for _tmp8 := range obj.Fixed {
+		w.WriteUint64(uint64(obj.Fixed[_tmp8]))
+	}
+	w.ListEnd(_tmp7)
+	_tmp9 := w.List()
+	for _tmp10 := range obj.Inners {
+		if err := obj.Inners[_tmp10].EncodeRLP(&w); err != nil {
+			return err
+		}
+	}
+	w.ListEnd(_tmp9)
+	_tmp11 := w.List()
+	for _tmp12 := range obj.Ptrs {
+		if err := obj.Ptrs[_tmp12].EncodeRLP(&w); err != nil {
+			return err
+		}
+	}
+	w.ListEnd(_tmp11)
+	_tmp13 := w.List()
+	for _tmp14 := range obj.Encoders {
+		if err := obj.Encoders[_tmp14].EncodeRLP(&w); err != nil {
+			return err
+		}
+	}
+	w.ListEnd(_tmp13)
+	_tmp15 := w.List()
+	for _tmp16 := range obj.Hashes {
+		w.WriteBytes(obj.Hashes[_tmp16][:])
+	}
+	w.ListEnd(_tmp15)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (obj *Lists) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp17 Lists
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp18 := []uint64{}
+	for dec.MoreDataInList() {
+		var _tmp19 uint64
+		_tmp20, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp19 = _tmp20
+		_tmp18 = append(_tmp18, _tmp19)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	_tmp17.Uints = _tmp18
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp21 := []string{}
+	for dec.MoreDataInList() {
+		var _tmp22 string
+		_tmp23, err := dec.Bytes()
+		if err != nil {
+			return err
+		}
+		_tmp22 = string(_tmp23)
+		_tmp21 = append(_tmp21, _tmp22)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	_tmp17.Strings = _tmp21
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp24 := [][]byte{}
+	for dec.MoreDataInList() {
+		var _tmp25 []byte
+		_tmp26, err := dec.Bytes()
+		if err != nil {
+			return err
+		}
+		_tmp25 = _tmp26
+		_tmp24 = append(_tmp24, _tmp25)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	_tmp17.Nested = _tmp24
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	for _tmp27 := range _tmp17.Fixed {
+		_tmp28, err := dec.Uint16()
+		if err != nil {
+			return err
+		}
+		_tmp17.Fixed[_tmp27] = _tmp28
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp29 := []Inner{}
+	for dec.MoreDataInList() {
+		var _tmp30 Inner
+		if err := _tmp30.DecodeRLP(dec); err != nil {
+			return err
+		}
+		_tmp29 = append(_tmp29, _tmp30)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	_tmp17.Inners = _tmp29
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp31 := []*Inner{}
+	for dec.MoreDataInList() {
+		var _tmp32 *Inner
+		if err := dec.Decode(&_tmp32); err != nil {
+			return err
+		}
+		_tmp31 = append(_tmp31, _tmp32)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	_tmp17.Ptrs = _tmp31
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp33 := []*Encodable{}
+	for dec.MoreDataInList() {
+		var _tmp34 *Encodable
+		if err := dec.Decode(&_tmp34); err != nil {
+			return err
+		}
+		_tmp33 = append(_tmp33, _tmp34)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	_tmp17.Encoders = _tmp33
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp35 := []common.Hash{}
+	for dec.MoreDataInList() {
+		var _tmp36 common.Hash
+		if err := dec.ReadBytes(_tmp36[:]); err != nil {
+			return err
+		}
+		_tmp35 = append(_tmp35, _tmp36)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	_tmp17.Hashes = _tmp35
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	*obj = _tmp17
+	return nil
+}
@@ -0,0 +1,130 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// EncodeRLP implements rlp.Encoder.
+func (obj *Optional) EncodeRLP(_w io.Writer) error {
+	if obj == nil {
+		_, err := _w.Write(rlp.EmptyList)
+		return err
+	}
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := obj.B != 0
+	_tmp1 := obj.C != nil
+	_tmp2 := obj.D != (common.Hash{})
+	_tmp3 := obj.E != nil
+	_tmp4 := obj.F != (Pair{})
+	_tmp5 := !reflect.ValueOf(&obj.G).Elem().IsZero()
+	_tmp6 := w.List()
+	w.WriteUint64(obj.A)
+	if _tmp0 || _tmp1 || _tmp2 || _tmp3 || _tmp4 || _tmp5 {
+		w.WriteUint64(obj.B)
+	}
+	if _tmp1 || _tmp2 || _tmp3 || _tmp4 || _tmp5 {
+		if obj.C == nil {
+			w.Write(rlp.EmptyString)
+		} else {
+			if obj.C.Sign() == -1 {
+				return rlp.ErrNegativeBigInt
+			}
+			w.WriteBigInt(obj.C)
+		}
+	}
+	if _tmp2 || _tmp3 || _tmp4 || _tmp5 {
+		w.WriteBytes(obj.D[:])
+	}
+	if _tmp3 || _tmp4 || _tmp5 {
+		w.WriteBytes(obj.E)
+	}
+	if _tmp4 || _tmp5 {
+		_tmp7 := w.List()
+		w.WriteUint64(obj.F.A)
+		w.WriteUint64(obj.F.B)
+		w.ListEnd(_tmp7)
+	}
+	if _tmp5 {
+		if obj.G.Sign() == -1 {
+			return rlp.ErrNegativeBigInt
+		}
+		w.WriteBigInt(&obj.G)
+	}
+	w.ListEnd(_tmp6)
+	return w.Flush()
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (obj *Optional) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp8 Optional
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	_tmp9, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	_tmp8.A = _tmp9
+	if dec.MoreDataInList() {
+		_tmp10, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp8.B = _tmp10
+	}
+	if dec.MoreDataInList() {
+		_tmp11, err := dec.BigInt()
+		if err != nil {
+			return err
+		}
+		_tmp8.C = _tmp11
+	}
+	if dec.MoreDataInList() {
+		if err := dec.ReadBytes(_tmp8.D[:]); err != nil {
+			return err
+		}
+	}
+	if dec.MoreDataInList() {
+		_tmp12, err := dec.Bytes()
+		if err != nil {
+			return err
+		}
+		_tmp8.E = _tmp12
+	}
+	if dec.MoreDataInList() {
+		if _, err := dec.List(); err != nil {
+			return err
+		}
+		_tmp13, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp8.F.A = _tmp13
+		_tmp14, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp8.F.B = _tmp14
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+	}
+	if dec.MoreDataInList() {
+		_tmp15, err := dec.BigInt()
+		if err != nil {
+			return err
+		}
+		_tmp8.G.Set(_tmp15)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	*obj = _tmp8
+	return nil
+}
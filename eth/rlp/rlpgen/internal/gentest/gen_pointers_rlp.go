@@ -0,0 +1,169 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"io"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// EncodeRLP implements rlp.Encoder.
+func (obj *Pointers) EncodeRLP(_w io.Writer) error {
+	if obj == nil {
+		_, err := _w.Write(rlp.EmptyList)
+		return err
+	}
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	if err := obj.Inner.EncodeRLP(&w); err != nil {
+		return err
+	}
+	if obj.NilPair == nil {
+		w.Write(rlp.EmptyList)
+	} else {
+		_tmp1 := w.List()
+		w.WriteUint64((*obj.NilPair).A)
+		w.WriteUint64((*obj.NilPair).B)
+		w.ListEnd(_tmp1)
+	}
+	if obj.Bytes == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		w.WriteBytes((*obj.Bytes)[:])
+	}
+	if obj.NilBytes == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		w.WriteBytes((*obj.NilBytes)[:])
+	}
+	if obj.Uint == nil {
+		var _tmp2 uint64
+		w.WriteUint64(_tmp2)
+	} else {
+		w.WriteUint64((*obj.Uint))
+	}
+	if obj.Addr == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		w.WriteBytes((*obj.Addr)[:])
+	}
+	if obj.Self == nil {
+		w.Write(rlp.EmptyList)
+	} else {
+		if err := rlp.Encode(&w, &(*obj.Self)); err != nil {
+			return err
+		}
+	}
+	for _tmp3 := range obj.Tail {
+		w.WriteUint64(obj.Tail[_tmp3])
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (obj *Pointers) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp4 Pointers
+	if _, err := dec.List(); err != nil {
+		return err
+	}
+	if err := dec.Decode(&_tmp4.Inner); err != nil {
+		return err
+	}
+	_tmp5, _tmp6, err := dec.Kind()
+	if err != nil {
+		return err
+	}
+	if _tmp6 == 0 && _tmp5 != rlp.Byte {
+		if _, err := dec.Raw(); err != nil {
+			return err
+		}
+		_tmp4.NilPair = nil
+	} else {
+		var _tmp7 Pair
+		if _, err := dec.List(); err != nil {
+			return err
+		}
+		_tmp8, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp7.A = _tmp8
+		_tmp9, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp7.B = _tmp9
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+		_tmp4.NilPair = &_tmp7
+	}
+	var _tmp10 [3]byte
+	if err := dec.ReadBytes(_tmp10[:]); err != nil {
+		return err
+	}
+	_tmp4.Bytes = &_tmp10
+	_tmp11, _tmp12, err := dec.Kind()
+	if err != nil {
+		return err
+	}
+	if _tmp12 == 0 && _tmp11 != rlp.Byte {
+		if _, err := dec.Raw(); err != nil {
+			return err
+		}
+		_tmp4.NilBytes = nil
+	} else {
+		var _tmp13 [3]byte
+		if err := dec.ReadBytes(_tmp13[:]); err != nil {
+			return err
+		}
+		_tmp4.NilBytes = &_tmp13
+	}
+	var _tmp14 uint64
+	_tmp15, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	_tmp14 = _tmp15
+	_tmp4.Uint = &_tmp14
+	var _tmp16 common.Address
+	if err := dec.ReadBytes(_tmp16[:]); err != nil {
+		return err
+	}
+	_tmp4.Addr = &_tmp16
+	_tmp17, _tmp18, err := dec.Kind()
+	if err != nil {
+		return err
+	}
+	if _tmp18 == 0 && _tmp17 != rlp.Byte {
+		if _, err := dec.Raw(); err != nil {
+			return err
+		}
+		_tmp4.Self = nil
+	} else {
+		var _tmp19 Pointers
+		if err := dec.Decode(&_tmp19); err != nil {
+			return err
+		}
+		_tmp4.Self = &_tmp19
+	}
+	_tmp20 := []uint64{}
+	for dec.MoreDataInList() {
+		var _tmp21 uint64
+		_tmp22, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp21 = _tmp22
+		_tmp20 = append(_tmp20, _tmp21)
+	}
+	_tmp4.Tail = _tmp20
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	*obj = _tmp4
+	return nil
+}
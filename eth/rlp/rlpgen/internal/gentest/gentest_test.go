@@ -0,0 +1,187 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gentest
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// The plain types have the same fields and tags as the generated types
+// but no methods, so package rlp encodes them through reflection.
+type (
+	plainBasic    Basic
+	plainLists    Lists
+	plainPointers Pointers
+	plainOptional Optional
+)
+
+type testValue struct {
+	gen, plain interface{}
+	encodeOnly bool // nil pointers inside lists don't survive a round trip
+}
+
+func testValues() []testValue {
+	basic := &Basic{
+		Uint8: 0x7f, Uint16: 0x80, Uint32: 0xffffff, Uint64: 1 << 63, Uint: 1024,
+		Bool:   true,
+		String: "a string longer than fifty-five bytes to get a long string header",
+		Bytes:  []byte{0},
+		Array:  [4]byte{1, 2, 3, 4},
+		Hash:   common.HexToHash("0x1234"),
+		Big:    new(big.Int).Lsh(big.NewInt(1), 200),
+		Named:  5,
+		NamedB: namedBytes{0x80},
+		Raw:    rlp.RawValue{0xC2, 0x01, 0x02},
+		Int:    300,
+	}
+	basic.BigVal.SetUint64(77)
+	lists := &Lists{
+		Uints:    []uint64{0, 1, 0x100},
+		Strings:  []string{"", "x"},
+		Nested:   [][]byte{nil, {1}},
+		Fixed:    [2]uint16{3, 0xffff},
+		Inners:   []Inner{{A: 1}, {B: []byte("b")}},
+		Ptrs:     []*Inner{{A: 2}},
+		Encoders: []*Encodable{{V: 9}},
+		Hashes:   []common.Hash{{1}},
+	}
+	u := uint64(42)
+	pointers := &Pointers{
+		Inner:    &Inner{A: 7, B: []byte{1, 2}},
+		Bytes:    &[3]byte{4, 5, 6},
+		NilBytes: &[3]byte{1, 2, 3},
+		Uint:     &u,
+		Addr:     &common.Address{0xaa},
+		Self:     &Pointers{Inner: &Inner{}, Bytes: &[3]byte{}, Addr: &common.Address{}, Tail: []uint64{1}},
+		Tail:     []uint64{1, 2, 3},
+	}
+	optional := &Optional{C: big.NewInt(0)}
+	optional.G.SetUint64(0)
+	nils := &Lists{
+		Ptrs:     []*Inner{nil},
+		Encoders: []*Encodable{nil},
+	}
+	return []testValue{
+		{&Basic{Raw: rlp.EmptyString}, &plainBasic{Raw: rlp.EmptyString}, false},
+		{basic, (*plainBasic)(basic), false},
+		{&Lists{}, &plainLists{}, false},
+		{lists, (*plainLists)(lists), false},
+		{nils, (*plainLists)(nils), true},
+		{&Pointers{}, &plainPointers{}, true},
+		{pointers, (*plainPointers)(pointers), false},
+		{(*Pointers)(nil), (*plainPointers)(nil), true},
+		{&Optional{}, &plainOptional{}, false},
+		{&Optional{A: 1, B: 2}, &plainOptional{A: 1, B: 2}, false},
+		{&Optional{D: common.Hash{1}}, &plainOptional{D: common.Hash{1}}, false},
+		{&Optional{E: []byte{}}, &plainOptional{E: []byte{}}, false},
+		{&Optional{F: Pair{B: 1}}, &plainOptional{F: Pair{B: 1}}, false},
+		{optional, (*plainOptional)(optional), false},
+	}
+}
+
+func TestEncodeMatchesReflection(t *testing.T) {
+	for i, tt := range testValues() {
+		got, err := rlp.EncodeToBytes(tt.gen)
+		if err != nil {
+			t.Fatalf("test %d: generated encoder error: %v", i, err)
+		}
+		want, err := rlp.EncodeToBytes(tt.plain)
+		if err != nil {
+			t.Fatalf("test %d: reflection encoder error: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("test %d: output mismatch\ngot  %x\nwant %x", i, got, want)
+		}
+	}
+}
+
+func TestDecodeMatchesReflection(t *testing.T) {
+	decoders := []struct{ gen, plain func() interface{} }{
+		{func() interface{} { return new(Basic) }, func() interface{} { return new(plainBasic) }},
+		{func() interface{} { return new(Lists) }, func() interface{} { return new(plainLists) }},
+		{func() interface{} { return new(Pointers) }, func() interface{} { return new(plainPointers) }},
+		{func() interface{} { return new(Optional) }, func() interface{} { return new(plainOptional) }},
+	}
+	for i, tt := range testValues() {
+		if tt.encodeOnly {
+			continue
+		}
+		enc, _ := rlp.EncodeToBytes(tt.plain)
+		var newGen, newPlain func() interface{}
+		switch tt.gen.(type) {
+		case *Basic:
+			newGen, newPlain = decoders[0].gen, decoders[0].plain
+		case *Lists:
+			newGen, newPlain = decoders[1].gen, decoders[1].plain
+		case *Pointers:
+			newGen, newPlain = decoders[2].gen, decoders[2].plain
+		case *Optional:
+			newGen, newPlain = decoders[3].gen, decoders[3].plain
+		}
+		gen, plain := newGen(), newPlain()
+		if err := rlp.DecodeBytes(enc, gen); err != nil {
+			t.Fatalf("test %d: generated decoder error: %v", i, err)
+		}
+		if err := rlp.DecodeBytes(enc, plain); err != nil {
+			t.Fatalf("test %d: reflection decoder error: %v", i, err)
+		}
+		// Compare the decoded values through their encoding.
+		genEnc, _ := rlp.EncodeToBytes(gen)
+		plainEnc, _ := rlp.EncodeToBytes(plain)
+		if !bytes.Equal(genEnc, enc) || !bytes.Equal(plainEnc, enc) {
+			t.Errorf("test %d: decoded value mismatch\ninput      %x\ngenerated  %x\nreflection %x", i, enc, genEnc, plainEnc)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []string{
+		"C0",           // too few elements
+		"C50102030405", // wrong element kinds
+		"C3C0C0C0C0",   // list size mismatch
+	}
+	for i, input := range tests {
+		b := common.FromHex(input)
+		if err := rlp.DecodeBytes(b, new(Pointers)); err == nil {
+			t.Errorf("test %d: expected error decoding %s", i, input)
+		}
+		if err := rlp.DecodeBytes(b, new(plainPointers)); err == nil {
+			t.Errorf("test %d: reflection decoder accepted %s", i, input)
+		}
+	}
+}
+
+func BenchmarkEncodeGenerated(b *testing.B) {
+	val := testValues()[3].gen
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(val)
+	}
+}
+
+func BenchmarkEncodeReflection(b *testing.B) {
+	val := testValues()[3].plain
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(val)
+	}
+}
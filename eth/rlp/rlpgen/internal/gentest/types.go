@@ -0,0 +1,123 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package gentest contains types used to check that the code generated by
+// rlpgen matches the reflection-based encoder.
+package gentest
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+//go:generate go run github.com/arcology-network/3rd-party/eth/rlp/rlpgen -type Inner -out gen_inner_rlp.go
+//go:generate go run github.com/arcology-network/3rd-party/eth/rlp/rlpgen -type Basic -out gen_basic_rlp.go
+//go:generate go run github.com/arcology-network/3rd-party/eth/rlp/rlpgen -type Lists -out gen_lists_rlp.go
+//go:generate go run github.com/arcology-network/3rd-party/eth/rlp/rlpgen -type Pointers -out gen_pointers_rlp.go
+//go:generate go run github.com/arcology-network/3rd-party/eth/rlp/rlpgen -type Optional -out gen_optional_rlp.go
+
+type namedUint uint32
+
+type namedBytes []byte
+
+type Basic struct {
+	Uint8   uint8
+	Uint16  uint16
+	Uint32  uint32
+	Uint64  uint64
+	Uint    uint
+	Bool    bool
+	String  string
+	Bytes   []byte
+	Array   [4]byte
+	Hash    common.Hash
+	Big     *big.Int
+	BigVal  big.Int
+	Named   namedUint
+	NamedB  namedBytes
+	Raw     rlp.RawValue
+	Int     int64
+	Ignored uint64 `rlp:"-"`
+	private uint64
+}
+
+type Inner struct {
+	A uint64
+	B []byte
+}
+
+// Pair has no generated methods, it is always inlined.
+type Pair struct {
+	A, B uint64
+}
+
+type Lists struct {
+	Uints    []uint64
+	Strings  []string
+	Nested   [][]byte
+	Fixed    [2]uint16
+	Inners   []Inner
+	Ptrs     []*Inner
+	Encoders []*Encodable
+	Hashes   []common.Hash
+}
+
+type Pointers struct {
+	Inner    *Inner
+	NilPair  *Pair `rlp:"nil"`
+	Bytes    *[3]byte
+	NilBytes *[3]byte `rlp:"nil"`
+	Uint     *uint64
+	Addr     *common.Address
+	Self     *Pointers `rlp:"nil"`
+	Tail     []uint64  `rlp:"tail"`
+}
+
+type Optional struct {
+	A uint64
+	B uint64      `rlp:"optional"`
+	C *big.Int    `rlp:"optional"`
+	D common.Hash `rlp:"optional"`
+	E []byte      `rlp:"optional"`
+	F Pair        `rlp:"optional"`
+	G big.Int     `rlp:"optional"`
+}
+
+// Encodable has hand-written encoding methods.
+type Encodable struct {
+	V uint64
+}
+
+func (e *Encodable) EncodeRLP(w io.Writer) error {
+	if e == nil {
+		return rlp.Encode(w, uint64(0))
+	}
+	return rlp.Encode(w, e.V+1)
+}
+
+func (e *Encodable) DecodeRLP(s *rlp.Stream) error {
+	v, err := s.Uint()
+	if err != nil {
+		return err
+	}
+	if v > 0 {
+		e.V = v - 1
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// rlpgen generates reflection-free EncodeRLP and DecodeRLP methods for
+// struct types. The generated code produces the same encoding as the
+// reflection-based encoder in package rlp and honors the "nil", "tail",
+// "optional" and "-" struct tags.
+//
+// Usage:
+//
+//	rlpgen -type MyStruct [-dir .] [-out gen_mystruct_rlp.go]
+//
+// A typical invocation from a go:generate directive is
+//
+//	//go:generate go run github.com/arcology-network/3rd-party/eth/rlp/rlpgen -type Header -out gen_header_rlp.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var (
+		dir      = flag.String("dir", ".", "input package directory")
+		typename = flag.String("type", "", "struct type to generate methods for")
+		output   = flag.String("out", "", "output file (default gen_<type>_rlp.go in the package directory, - for stdout)")
+		encoder  = flag.Bool("encoder", true, "generate EncodeRLP")
+		decoder  = flag.Bool("decoder", true, "generate DecodeRLP")
+	)
+	flag.Parse()
+
+	if *typename == "" {
+		fatal("-type is required")
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(*dir, "gen_"+strings.ToLower(*typename)+"_rlp.go")
+	} else if out != "-" && !filepath.IsAbs(out) && filepath.Dir(out) == "." {
+		out = filepath.Join(*dir, out)
+	}
+
+	cfg := Config{Dir: *dir, Type: *typename, Encoder: *encoder, Decoder: *decoder}
+	if out != "-" {
+		cfg.Exclude = out
+	}
+	code, err := cfg.Process()
+	if err != nil {
+		fatal(err)
+	}
+	if out == "-" {
+		os.Stdout.Write(code)
+		return
+	}
+	if err := ioutil.WriteFile(out, code, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, append([]interface{}{"rlpgen:"}, args...)...)
+	os.Exit(1)
+}
@@ -41,6 +41,9 @@ type tags struct {
 	// elements. It can only be set for the last field, which must be
 	// of slice type.
 	tail bool
+	// rlp:"optional" allows for a field to be missing in the input list.
+	// If this is set, all subsequent fields must also be optional.
+	optional bool
 	// rlp:"-" ignores fields.
 	ignored bool
 }
@@ -91,11 +94,13 @@ func cachedTypeInfo1(typ reflect.Type, tags tags) (*typeinfo, error) {
 }
 
 type field struct {
-	index int
-	info  *typeinfo
+	index    int
+	info     *typeinfo
+	optional bool
 }
 
 func structFields(typ reflect.Type) (fields []field, err error) {
+	var lastOptional string // name of the last optional field seen
 	for i := 0; i < typ.NumField(); i++ {
 		if f := typ.Field(i); f.PkgPath == "" { // exported
 			tags, err := parseStructTag(typ, i)
@@ -105,16 +110,32 @@ func structFields(typ reflect.Type) (fields []field, err error) {
 			if tags.ignored {
 				continue
 			}
+			// Optional fields must be contiguous at the end of the struct.
+			if tags.optional {
+				lastOptional = f.Name
+			} else if lastOptional != "" {
+				return nil, fmt.Errorf(`rlp: invalid struct tag "" for %v.%s (must be optional because preceding field %q is optional)`, typ, f.Name, lastOptional)
+			}
 			info, err := cachedTypeInfo1(f.Type, tags)
 			if err != nil {
 				return nil, err
 			}
-			fields = append(fields, field{i, info})
+			fields = append(fields, field{i, info, tags.optional})
 		}
 	}
 	return fields, nil
 }
 
+// firstOptionalField returns the index of the first field with "optional" tag.
+func firstOptionalField(fields []field) int {
+	for i, f := range fields {
+		if f.optional {
+			return i
+		}
+	}
+	return len(fields)
+}
+
 func parseStructTag(typ reflect.Type, fi int) (tags, error) {
 	f := typ.Field(fi)
 	var ts tags
@@ -133,10 +154,15 @@ func parseStructTag(typ reflect.Type, fi int) (tags, error) {
 			if f.Type.Kind() != reflect.Slice {
 				return ts, fmt.Errorf(`rlp: invalid struct tag "tail" for %v.%s (field type is not slice)`, typ, f.Name)
 			}
+		case "optional":
+			ts.optional = true
 		default:
 			return ts, fmt.Errorf("rlp: unknown struct tag %q on %v.%s", t, typ, f.Name)
 		}
 	}
+	if ts.optional && ts.tail {
+		return ts, fmt.Errorf(`rlp: invalid struct tag "optional" for %v.%s (also has "tail" tag)`, typ, f.Name)
+	}
 	return ts, nil
 }
 
@@ -0,0 +1,78 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import "fmt"
+
+// CanonError is returned by Validate, DecodeBytesStrict and streams in
+// strict mode for the first encoding violation in their input.
+type CanonError struct {
+	Offset int   // offset of the offending value's header in the input
+	Err    error // reason, e.g. ErrCanonSize
+}
+
+func (e *CanonError) Error() string {
+	return fmt.Sprintf("%v (at offset %d)", e.Err, e.Offset)
+}
+
+// Unwrap returns the reason of the violation.
+func (e *CanonError) Unwrap() error { return e.Err }
+
+// Validate checks that b holds exactly one RLP value in canonical form,
+// including all nested values: sizes are encoded minimally and without
+// leading zero bytes, single bytes below 0x80 are not wrapped as strings,
+// no element overruns its enclosing list and no bytes follow the value.
+// Any violation is reported as a *CanonError.
+//
+// Validate does not know the type the value decodes into, so it cannot
+// check integer contents for leading zeros. Use DecodeBytesStrict to
+// check those as well.
+func Validate(b []byte) error {
+	k, ts, cs, err := readKind(b)
+	if err != nil {
+		return &CanonError{Offset: 0, Err: err}
+	}
+	if end := ts + cs; end < uint64(len(b)) {
+		return &CanonError{Offset: int(end), Err: ErrMoreThanOneValue}
+	}
+	if k == List {
+		return validateList(b[ts:], int(ts))
+	}
+	return nil
+}
+
+// validateList checks the elements of a list, whose content starts at the
+// given offset in the original input.
+func validateList(content []byte, offset int) error {
+	for pos := offset; len(content) > 0; {
+		k, ts, cs, err := readKind(content)
+		if err != nil {
+			if err == ErrValueTooLarge {
+				err = ErrElemTooLarge
+			}
+			return &CanonError{Offset: pos, Err: err}
+		}
+		if k == List {
+			if err := validateList(content[ts:ts+cs], pos+int(ts)); err != nil {
+				return err
+			}
+		}
+		content = content[ts+cs:]
+		pos += int(ts + cs)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int
+		err    error
+	}{
+		// canonical
+		{input: "00"},
+		{input: "80"},
+		{input: "8180"},
+		{input: "C0"},
+		{input: "C3C20102"},
+		{input: "B838" + "6161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161"},
+
+		// violations at the top level
+		{input: "", offset: 0, err: io.ErrUnexpectedEOF},
+		{input: "8100", offset: 0, err: ErrCanonSize},
+		{input: "B80100", offset: 0, err: ErrCanonSize},
+		{input: "B90001" + "00", offset: 0, err: ErrCanonSize},
+		{input: "8201", offset: 0, err: ErrValueTooLarge},
+		{input: "0102", offset: 1, err: ErrMoreThanOneValue},
+		{input: "C0C0", offset: 1, err: ErrMoreThanOneValue},
+
+		// violations in nested values
+		{input: "C3018100", offset: 2, err: ErrCanonSize},
+		{input: "C401C28100", offset: 3, err: ErrCanonSize},
+		{input: "C3C28201", offset: 2, err: ErrElemTooLarge},
+		{input: "C401F80100", offset: 2, err: ErrCanonSize},
+	}
+	for _, test := range tests {
+		err := Validate(unhex(test.input))
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("input %s: unexpected error %v", test.input, err)
+			}
+			continue
+		}
+		var cerr *CanonError
+		if !errors.As(err, &cerr) {
+			t.Errorf("input %s: got error %v, want *CanonError", test.input, err)
+			continue
+		}
+		if cerr.Offset != test.offset || !errors.Is(err, test.err) {
+			t.Errorf("input %s: got %v, want %v at offset %d", test.input, err, test.err, test.offset)
+		}
+	}
+}
+
+func TestDecodeBytesStrict(t *testing.T) {
+	type value struct {
+		A uint64
+		B *big.Int
+	}
+	tests := []struct {
+		input  string
+		offset int
+		err    error
+	}{
+		{input: "C20102"},
+		{input: "C20180"},
+		{input: "C3018100", offset: 2, err: ErrCanonSize},
+		{input: "C20100", offset: 2, err: ErrCanonInt},
+		{input: "C401820001", offset: 2, err: ErrCanonInt},
+		{input: "C20001", offset: 1, err: ErrCanonInt},
+		{input: "C482000101", offset: 1, err: ErrCanonInt},
+		{input: "C20102C0", offset: 3, err: ErrMoreThanOneValue},
+	}
+	for _, test := range tests {
+		err := DecodeBytesStrict(unhex(test.input), new(value))
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("input %s: unexpected error %v", test.input, err)
+			}
+			continue
+		}
+		var cerr *CanonError
+		if !errors.As(err, &cerr) {
+			t.Errorf("input %s: got error %v, want *CanonError", test.input, err)
+			continue
+		}
+		if cerr.Offset != test.offset || !errors.Is(err, test.err) {
+			t.Errorf("input %s: got %v, want %v at offset %d", test.input, err, test.err, test.offset)
+		}
+	}
+}
+
+func TestStreamStrict(t *testing.T) {
+	input := unhex("C401820001")
+	s := NewStream(bytes.NewReader(input), 0)
+	if _, err := s.List(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Uint64(); err != nil {
+		t.Fatal(err)
+	}
+	s.SetStrict(true)
+	_, err := s.Uint64()
+	var cerr *CanonError
+	if !errors.As(err, &cerr) || cerr.Offset != 2 || cerr.Err != ErrCanonInt {
+		t.Fatalf("got error %v, want ErrCanonInt at offset 2", err)
+	}
+
+	s.Reset(bytes.NewReader(input), 0)
+	if s.Strict() {
+		t.Fatal("strict mode not cleared by Reset")
+	}
+}
@@ -32,6 +32,9 @@ import (
 	"github.com/arcology-network/3rd-party/eth/rlp"
 )
 
+// EmptyUncleHash is the UncleHash of blocks without uncles.
+var EmptyUncleHash = rlpHash([]*Header(nil))
+
 // A BlockNonce is a 64-bit hash which proves (combined with the
 // mix-hash) that a sufficient amount of computation has been carried
 // out on a block.
@@ -78,6 +81,9 @@ type Header struct {
 	Extra       []byte         `json:"extraData"        gencodec:"required"`
 	MixDigest   common.Hash    `json:"mixHash"`
 	Nonce       BlockNonce     `json:"nonce"`
+
+	// BaseFee was added by EIP-1559 and is ignored in legacy headers.
+	BaseFee *big.Int `json:"baseFeePerGas,omitempty" rlp:"optional"`
 }
 
 // field type overrides for gencodec
@@ -88,6 +94,7 @@ type headerMarshaling struct {
 	GasUsed    hexutil.Uint64
 	Time       *hexutil.Big
 	Extra      hexutil.Bytes
+	BaseFee    *hexutil.Big
 	Hash       common.Hash `json:"hash"` // adds call to Hash() in MarshalJSON
 }
 
@@ -100,7 +107,11 @@ func (h *Header) Hash() common.Hash {
 // Size returns the approximate memory used by all internal contents. It is used
 // to approximate and limit the memory consumption of various caches.
 func (h *Header) Size() common.StorageSize {
-	return common.StorageSize(unsafe.Sizeof(*h)) + common.StorageSize(len(h.Extra)+(h.Difficulty.BitLen()+h.Number.BitLen()+h.Time.BitLen())/8)
+	var baseFeeBits int
+	if h.BaseFee != nil {
+		baseFeeBits = h.BaseFee.BitLen()
+	}
+	return common.StorageSize(unsafe.Sizeof(*h)) + common.StorageSize(len(h.Extra)+(h.Difficulty.BitLen()+h.Number.BitLen()+h.Time.BitLen()+baseFeeBits)/8)
 }
 
 func rlpHash(x interface{}) (h common.Hash) {
@@ -110,6 +121,16 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+// It's used for typed transactions.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	hw := sha3.NewKeccak256()
+	hw.Write([]byte{prefix})
+	rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
+
 // Body is a simple (mutable, non-safe) data container for storing and moving
 // a block's data contents (transactions and uncles) together.
 type Body struct {
@@ -194,102 +215,28 @@ type storageblock struct {
 // The values of TxHash, UncleHash, ReceiptHash and Bloom in header
 // are ignored and set to values derived from the given txs, uncles
 // and receipts.
-// func NewBlock(header *Header, txs []*Transaction, uncles []*Header, receipts []*Receipt) *Block {
-// 	b := &Block{header: CopyHeader(header), td: new(big.Int)}
-
-// 	// TODO: panic if len(txs) != len(receipts)
-
-// 	txrh, rcrh := getHashRoot(txs, receipts)
-
-// 	if len(txs) == 0 {
-// 		b.header.TxHash = EmptyRootHash
-// 	} else {
-// 		//b.header.TxHash = DeriveSha(Transactions(txs))
-// 		b.header.TxHash = txrh
-// 		b.transactions = make(Transactions, len(txs))
-// 		copy(b.transactions, txs)
-// 	}
-
-// 	if len(receipts) == 0 {
-// 		b.header.ReceiptHash = EmptyRootHash
-// 	} else {
-// 		//b.header.ReceiptHash = DeriveSha(Receipts(receipts))
-// 		b.header.ReceiptHash = rcrh
-// 		b.header.Bloom = CreateBloom(receipts)
-// 	}
-
-// 	if len(uncles) == 0 {
-// 		b.header.UncleHash = EmptyUncleHash
-// 	} else {
-// 		b.header.UncleHash = CalcUncleHash(uncles)
-// 		b.uncles = make([]*Header, len(uncles))
-// 		for i := range uncles {
-// 			b.uncles[i] = CopyHeader(uncles[i])
-// 		}
-// 	}
-
-// 	return b
-// }
+func NewBlock(header *Header, txs []*Transaction, uncles []*Header, receipts []*Receipt) *Block {
+	b := &Block{header: CopyHeader(header), td: new(big.Int)}
 
-type HashResult struct {
-	style    int
-	roothash common.Hash
+	b.header.TxHash, b.header.ReceiptHash = DeriveRoots(txs, receipts)
+	if len(txs) > 0 {
+		b.transactions = make(Transactions, len(txs))
+		copy(b.transactions, txs)
+	}
+	b.header.Bloom = CreateBloom(receipts)
+
+	if len(uncles) == 0 {
+		b.header.UncleHash = EmptyUncleHash
+	} else {
+		b.header.UncleHash = CalcUncleHash(uncles)
+		b.uncles = make([]*Header, len(uncles))
+		for i := range uncles {
+			b.uncles[i] = CopyHeader(uncles[i])
+		}
+	}
+	return b
 }
 
-const (
-	HashResultStyle_Tx = 0
-	HashResultStyle_Rc = 1
-)
-
-// func hashWorker(txs []*Transaction, receipts []*Receipt, style int, jobs <-chan int, results chan<- HashResult) {
-
-// 	for _ = range jobs {
-// 		result := HashResult{}
-// 		if style == HashResultStyle_Tx {
-// 			result.style = HashResultStyle_Tx
-// 			result.roothash = DeriveSha(Transactions(txs))
-// 		} else {
-// 			result.style = HashResultStyle_Rc
-// 			result.roothash = DeriveSha(Receipts(receipts))
-// 		}
-
-// 		results <- result
-// 	}
-// }
-
-//mutilple thread compute transactions roothash and recipts roothash
-// func getHashRoot(txs []*Transaction, receipts []*Receipt) (common.Hash, common.Hash) {
-// 	threadnum := 2 // get cpu core num
-// 	jobs := make(chan int, threadnum)
-
-// 	//fmt.Printf("maxProcs=%d\n", threadnum)
-// 	results := make(chan HashResult, threadnum)
-
-// 	go hashWorker(txs, nil, HashResultStyle_Tx, jobs, results)
-// 	go hashWorker(nil, receipts, HashResultStyle_Rc, jobs, results)
-
-// 	// add jobs,start exec
-// 	for j := 0; j < threadnum; j++ {
-// 		jobs <- j
-// 	}
-// 	//close Channel
-// 	close(jobs)
-
-// 	txrh := common.Hash{}
-// 	rcrh := common.Hash{}
-// 	//for order result
-// 	for a := 0; a < threadnum; a++ {
-// 		res := <-results
-// 		if res.style == HashResultStyle_Tx {
-// 			txrh = res.roothash
-// 		} else {
-// 			rcrh = res.roothash
-// 		}
-// 	}
-// 	return txrh, rcrh
-
-// }
-
 // NewBlockWithHeader creates a block with the given header data. The
 // header data is copied, changes to header and to the field values
 // will not affect the block.
@@ -314,6 +261,9 @@ func CopyHeader(h *Header) *Header {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	return &cpy
 }
 
@@ -368,6 +318,14 @@ func (b *Block) GasUsed() uint64      { return b.header.GasUsed }
 func (b *Block) Difficulty() *big.Int { return new(big.Int).Set(b.header.Difficulty) }
 func (b *Block) Time() *big.Int       { return new(big.Int).Set(b.header.Time) }
 
+// BaseFee returns the EIP-1559 base fee of the block, or nil before London.
+func (b *Block) BaseFee() *big.Int {
+	if b.header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(b.header.BaseFee)
+}
+
 func (b *Block) NumberU64() uint64        { return b.header.Number.Uint64() }
 func (b *Block) MixDigest() common.Hash   { return b.header.MixDigest }
 func (b *Block) Nonce() uint64            { return binary.BigEndian.Uint64(b.header.Nonce[:]) }
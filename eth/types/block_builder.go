@@ -0,0 +1,126 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+var (
+	// ErrGasLimitReached is returned by BlockBuilder.AddTransaction if the
+	// gas used by a transaction exceeds the gas left in the block.
+	ErrGasLimitReached = errors.New("gas limit reached")
+
+	// ErrReceiptGasUsed is returned by BlockBuilder.AddTransaction if a
+	// receipt reports more gas used than its transaction provides.
+	ErrReceiptGasUsed = errors.New("receipt gas used exceeds transaction gas")
+)
+
+// BlockBuilder assembles a block from executed transactions and their
+// receipts. It keeps the gas used, the roots and the bloom of the header
+// consistent with the block contents, and fills in the fields locating
+// receipts and logs in the block.
+type BlockBuilder struct {
+	header   *Header
+	txs      Transactions
+	receipts Receipts
+	uncles   []*Header
+	logs     uint // number of logs in the block so far
+}
+
+// NewBlockBuilder creates a builder for an empty block on top of the given
+// header, which is copied. The gas used of the header is reset, and its
+// roots and bloom are derived when the block is built.
+func NewBlockBuilder(header *Header) *BlockBuilder {
+	h := CopyHeader(header)
+	h.GasUsed = 0
+	return &BlockBuilder{header: h}
+}
+
+// AddTransaction appends tx and its receipt to the block. The receipt must
+// hold the gas used by tx and the logs it emitted. The builder sets its
+// type, transaction hash, cumulative gas used, bloom and position, as well
+// as the derived fields of its logs. Nothing is changed if an error is
+// returned.
+func (b *BlockBuilder) AddTransaction(tx *Transaction, receipt *Receipt) error {
+	if receipt.GasUsed > tx.Gas() {
+		return ErrReceiptGasUsed
+	}
+	if b.header.GasLimit-b.header.GasUsed < receipt.GasUsed {
+		return ErrGasLimitReached
+	}
+	b.header.GasUsed += receipt.GasUsed
+
+	index := uint(len(b.txs))
+	receipt.Type = tx.Type()
+	receipt.TxHash = tx.Hash()
+	receipt.CumulativeGasUsed = b.header.GasUsed
+	receipt.BlockNumber = new(big.Int).Set(b.header.Number)
+	receipt.TransactionIndex = index
+	receipt.Bloom = Bloom{}
+	for _, log := range receipt.Logs {
+		log.BlockNumber = b.header.Number.Uint64()
+		log.TxHash, log.TxIndex, log.Index = receipt.TxHash, index, b.logs
+		b.logs++
+
+		receipt.Bloom.AddValue(log.Address[:])
+		for _, topic := range log.Topics {
+			receipt.Bloom.AddValue(topic[:])
+		}
+	}
+	b.txs = append(b.txs, tx)
+	b.receipts = append(b.receipts, receipt)
+	return nil
+}
+
+// AddUncle appends a copy of uncle to the uncles of the block.
+func (b *BlockBuilder) AddUncle(uncle *Header) {
+	b.uncles = append(b.uncles, CopyHeader(uncle))
+}
+
+// SetRoot sets the state root of the block, which is usually only known
+// once all transactions have been executed.
+func (b *BlockBuilder) SetRoot(root common.Hash) {
+	b.header.Root = root
+}
+
+// GasUsed returns the gas used by the transactions added so far.
+func (b *BlockBuilder) GasUsed() uint64 { return b.header.GasUsed }
+
+// Transactions returns the transactions added so far.
+func (b *BlockBuilder) Transactions() Transactions { return b.txs }
+
+// Receipts returns the receipts added so far.
+func (b *BlockBuilder) Receipts() Receipts { return b.receipts }
+
+// Build returns the block holding the transactions and uncles added so far
+// and sets the block hash in the receipts and their logs. The builder can
+// be used further, a later call to Build updates the block hash.
+func (b *BlockBuilder) Build() *Block {
+	block := NewBlock(b.header, b.txs, b.uncles, b.receipts)
+	hash := block.Hash()
+	for _, receipt := range b.receipts {
+		receipt.BlockHash = hash
+		for _, log := range receipt.Logs {
+			log.BlockHash = hash
+		}
+	}
+	return block
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+func TestNewBlockEmpty(t *testing.T) {
+	header := &Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Time: big.NewInt(0), TxHash: common.Hash{1}, UncleHash: common.Hash{2}}
+	block := NewBlock(header, nil, nil, nil)
+	if block.TxHash() != EmptyRootHash || block.ReceiptHash() != EmptyRootHash {
+		t.Errorf("wrong roots %x %x", block.TxHash(), block.ReceiptHash())
+	}
+	if want := common.HexToHash("1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"); block.UncleHash() != want {
+		t.Errorf("wrong uncle hash %x", block.UncleHash())
+	}
+	if block.Bloom() != (Bloom{}) {
+		t.Error("bloom of empty block not empty")
+	}
+	if header.TxHash != (common.Hash{1}) {
+		t.Error("NewBlock modified the header")
+	}
+}
+
+func TestBlockBuilder(t *testing.T) {
+	header := &Header{Number: big.NewInt(7), Difficulty: big.NewInt(1), Time: big.NewInt(0), GasLimit: 50000, GasUsed: 1}
+	b := NewBlockBuilder(header)
+
+	txs := []*Transaction{
+		NewTransaction(0, testAddr, big.NewInt(1), 21000, big.NewInt(1), nil),
+		NewTransaction(1, testAddr, big.NewInt(1), 25000, big.NewInt(1), nil),
+		NewTransaction(2, testAddr, big.NewInt(1), 21000, big.NewInt(1), nil),
+	}
+	receipts := []*Receipt{
+		{Status: ReceiptStatusSuccessful, GasUsed: 21000, Logs: []*Log{
+			{Address: common.Address{1}, Topics: []common.Hash{{1}}},
+			{Address: common.Address{2}},
+		}},
+		{Status: ReceiptStatusFailed, GasUsed: 22000},
+		{Status: ReceiptStatusSuccessful, GasUsed: 21000, Logs: []*Log{{Address: common.Address{3}}}},
+	}
+	if err := b.AddTransaction(txs[0], receipts[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddTransaction(txs[1], &Receipt{GasUsed: 30000}); err != ErrReceiptGasUsed {
+		t.Fatalf("got error %v, want ErrReceiptGasUsed", err)
+	}
+	if err := b.AddTransaction(txs[1], receipts[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddTransaction(txs[2], receipts[2]); err != ErrGasLimitReached {
+		t.Fatalf("got error %v, want ErrGasLimitReached", err)
+	}
+	if b.GasUsed() != 43000 || len(b.Transactions()) != 2 || len(b.Receipts()) != 2 {
+		t.Fatalf("failed additions changed the block: gas used %d", b.GasUsed())
+	}
+	b.AddUncle(&Header{Number: big.NewInt(6), Difficulty: big.NewInt(1), Time: big.NewInt(0)})
+	b.SetRoot(common.Hash{9})
+	block := b.Build()
+
+	if block.GasUsed() != 43000 || block.Root() != (common.Hash{9}) || block.NumberU64() != 7 {
+		t.Errorf("wrong header %+v", block.Header())
+	}
+	if block.TxHash() != DeriveSha(Transactions(txs[:2])) {
+		t.Error("wrong transaction root")
+	}
+	if block.ReceiptHash() != DeriveSha(Receipts(receipts[:2])) {
+		t.Error("wrong receipt root")
+	}
+	if block.Bloom() != CreateBloom(receipts[:2]) {
+		t.Error("wrong bloom")
+	}
+	if block.UncleHash() != CalcUncleHash(block.Uncles()) || len(block.Uncles()) != 1 {
+		t.Error("wrong uncles")
+	}
+	for i, r := range receipts[:2] {
+		if r.TxHash != txs[i].Hash() || r.TransactionIndex != uint(i) || r.BlockHash != block.Hash() || r.BlockNumber.Uint64() != 7 {
+			t.Errorf("receipt %d: wrong derived fields %+v", i, r)
+		}
+		if r.Bloom != BytesToBloom(LogsBloom(r.Logs).Bytes()) {
+			t.Errorf("receipt %d: wrong bloom", i)
+		}
+	}
+	if receipts[0].CumulativeGasUsed != 21000 || receipts[1].CumulativeGasUsed != 43000 {
+		t.Error("wrong cumulative gas used")
+	}
+	for i, log := range receipts[0].Logs {
+		if log.Index != uint(i) || log.TxHash != txs[0].Hash() || log.BlockHash != block.Hash() || log.BlockNumber != 7 {
+			t.Errorf("log %d: wrong derived fields %+v", i, log)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+func TestHeaderBaseFeeEncoding(t *testing.T) {
+	legacy := &Header{Difficulty: big.NewInt(1), Number: big.NewInt(2), Time: big.NewInt(3), Extra: []byte{}}
+	london := CopyHeader(legacy)
+	london.BaseFee = big.NewInt(1000000000)
+
+	for _, header := range []*Header{legacy, london} {
+		enc, err := rlp.EncodeToBytes(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dec Header
+		if err := rlp.DecodeBytes(enc, &dec); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(&dec, header) || dec.Hash() != header.Hash() {
+			t.Errorf("round trip mismatch: have %+v, want %+v", dec, header)
+		}
+	}
+	// Legacy headers keep their 15 field encoding and hash.
+	fields := func(h *Header) int {
+		enc, _ := rlp.EncodeToBytes(h)
+		n, _ := rlp.CountValues(mustSplitList(t, enc))
+		return n
+	}
+	if n := fields(legacy); n != 15 {
+		t.Errorf("legacy header has %d fields, want 15", n)
+	}
+	if n := fields(london); n != 16 {
+		t.Errorf("London header has %d fields, want 16", n)
+	}
+	if cpy := CopyHeader(london); cpy.BaseFee == london.BaseFee || cpy.BaseFee.Cmp(london.BaseFee) != 0 {
+		t.Error("CopyHeader does not deep copy the base fee")
+	}
+	if block := NewBlockWithHeader(london); block.BaseFee().Cmp(london.BaseFee) != 0 || NewBlockWithHeader(legacy).BaseFee() != nil {
+		t.Error("wrong block base fee")
+	}
+}
+
+func mustSplitList(t *testing.T, enc []byte) []byte {
+	content, _, err := rlp.SplitList(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
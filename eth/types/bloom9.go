@@ -19,6 +19,7 @@ package types
 import (
 	"fmt"
 	"math/big"
+	mathbits "math/bits"
 
 	"github.com/arcology-network/3rd-party/eth/common/hexutil"
 	"github.com/arcology-network/3rd-party/eth/crypto"
@@ -134,3 +135,107 @@ func BloomLookup(bin Bloom, topic bytesBacked) bool {
 
 	return bloom.And(bloom, cmp).Cmp(cmp) == 0
 }
+
+// BloomBits holds the positions of the three bloom bits set by a value, so
+// that they are hashed once and then tested or added directly on the bytes
+// of any number of blooms.
+type BloomBits struct {
+	index [3]uint // byte indices into Bloom
+	mask  [3]byte // bit masks within those bytes
+}
+
+// NewBloomBits computes the bloom bits of data.
+func NewBloomBits(data []byte) BloomBits {
+	h := crypto.Keccak256(data)
+	var bits BloomBits
+	for i := 0; i < 3; i++ {
+		bit := (uint(h[2*i])<<8 | uint(h[2*i+1])) & 2047
+		bits.index[i] = BloomByteLength - 1 - bit/8
+		bits.mask[i] = 1 << (bit % 8)
+	}
+	return bits
+}
+
+// Bits returns the bit numbers, counting from the least significant bit of
+// the bloom as in Big.
+func (bits BloomBits) Bits() [3]uint {
+	var n [3]uint
+	for i := range n {
+		n[i] = (BloomByteLength-1-bits.index[i])*8 + uint(mathbits.TrailingZeros8(bits.mask[i]))
+	}
+	return n
+}
+
+// AddBits sets the given bloom bits in b.
+func (b *Bloom) AddBits(bits BloomBits) {
+	b[bits.index[0]] |= bits.mask[0]
+	b[bits.index[1]] |= bits.mask[1]
+	b[bits.index[2]] |= bits.mask[2]
+}
+
+// TestBits reports whether all of the given bloom bits are set in b.
+func (b *Bloom) TestBits(bits BloomBits) bool {
+	return b[bits.index[0]]&bits.mask[0] == bits.mask[0] &&
+		b[bits.index[1]]&bits.mask[1] == bits.mask[1] &&
+		b[bits.index[2]]&bits.mask[2] == bits.mask[2]
+}
+
+// AddValue adds the bloom bits of data to b. It is equivalent to Add on the
+// same bytes without going through big.Int.
+func (b *Bloom) AddValue(data []byte) {
+	b.AddBits(NewBloomBits(data))
+}
+
+// TestValue reports whether data may have been added to b. It is equivalent
+// to BloomLookup without going through big.Int.
+func (b *Bloom) TestValue(data []byte) bool {
+	return b.TestBits(NewBloomBits(data))
+}
+
+// BloomMatcher tests one set of criteria against many blooms. The criteria
+// are groups of alternative values: a bloom matches if, for every group, it
+// contains any of the group's values. Empty groups match every bloom.
+type BloomMatcher struct {
+	groups [][]BloomBits
+}
+
+// NewBloomMatcher computes the bloom bits of all values in groups.
+func NewBloomMatcher(groups [][][]byte) *BloomMatcher {
+	m := &BloomMatcher{groups: make([][]BloomBits, 0, len(groups))}
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		bits := make([]BloomBits, len(group))
+		for i, value := range group {
+			bits[i] = NewBloomBits(value)
+		}
+		m.groups = append(m.groups, bits)
+	}
+	return m
+}
+
+// Match reports whether bloom matches the criteria.
+func (m *BloomMatcher) Match(bloom *Bloom) bool {
+Groups:
+	for _, group := range m.groups {
+		for _, bits := range group {
+			if bloom.TestBits(bits) {
+				continue Groups
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// MatchAll returns the indices of the blooms matching the criteria.
+func (m *BloomMatcher) MatchAll(blooms []Bloom) []int {
+	var matches []int
+	for i := range blooms {
+		if m.Match(&blooms[i]) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
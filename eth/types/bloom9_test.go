@@ -0,0 +1,172 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+func TestBloomBits(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		value := []byte(fmt.Sprintf("value %d", i))
+		bits := NewBloomBits(value)
+
+		// The bit numbers must agree with bloom9.
+		want := bloom9(value)
+		got := new(big.Int)
+		for _, bit := range bits.Bits() {
+			got.SetBit(got, int(bit), 1)
+		}
+		if got.Cmp(want) != 0 {
+			t.Fatalf("%q: bits %v disagree with bloom9", value, bits.Bits())
+		}
+
+		// Adding through bytes and big.Int must give the same bloom.
+		var byteBloom, bigBloom Bloom
+		byteBloom.AddValue(value)
+		bigBloom.Add(new(big.Int).SetBytes(value))
+		if byteBloom != bigBloom {
+			t.Fatalf("%q: AddValue and Add disagree", value)
+		}
+		other := []byte(fmt.Sprintf("other %d", i))
+		if !byteBloom.TestValue(value) || byteBloom.TestValue(other) != BloomLookup(byteBloom, bytesBackedSlice(other)) {
+			t.Fatalf("%q: TestValue disagrees with BloomLookup", value)
+		}
+	}
+}
+
+func TestBloomTestValue(t *testing.T) {
+	var bloom Bloom
+	positive := []string{"testtest", "test", "hallo", "other"}
+	negative := []string{"tes", "lo"}
+	for _, data := range positive {
+		bloom.AddValue([]byte(data))
+	}
+	for _, data := range positive {
+		if !bloom.TestValue([]byte(data)) {
+			t.Errorf("expected %q to test true", data)
+		}
+	}
+	for _, data := range negative {
+		if bloom.TestValue([]byte(data)) {
+			t.Errorf("expected %q to test false", data)
+		}
+		if bloom.TestValue([]byte(data)) != BloomLookup(bloom, bytesBackedSlice(data)) {
+			t.Errorf("%q: TestValue disagrees with BloomLookup", data)
+		}
+	}
+}
+
+type bytesBackedSlice []byte
+
+func (b bytesBackedSlice) Bytes() []byte { return b }
+
+func TestBloomMatcher(t *testing.T) {
+	a, b, c := []byte("a"), []byte("b"), []byte("c")
+	blooms := make([]Bloom, 4)
+	blooms[0].AddValue(a)
+	blooms[1].AddValue(b)
+	blooms[2].AddValue(a)
+	blooms[2].AddValue(c)
+	for i, test := range []struct {
+		groups [][][]byte
+		want   string
+	}{
+		{nil, "[0 1 2 3]"},
+		{[][][]byte{{}}, "[0 1 2 3]"},
+		{[][][]byte{{a}}, "[0 2]"},
+		{[][][]byte{{a, b}}, "[0 1 2]"},
+		{[][][]byte{{a}, {c}}, "[2]"},
+		{[][][]byte{{a}, {}, {b, c}}, "[2]"},
+		{[][][]byte{{[]byte("d")}}, "[]"},
+	} {
+		if got := fmt.Sprint(NewBloomMatcher(test.groups).MatchAll(blooms)); got != test.want {
+			t.Errorf("test %d: got %s, want %s", i, got, test.want)
+		}
+	}
+}
+
+func makeBenchBlooms(n int) ([]Bloom, []common.Hash) {
+	rnd := rand.New(rand.NewSource(1))
+	blooms := make([]Bloom, n)
+	topics := make([]common.Hash, 16)
+	for i := range topics {
+		rnd.Read(topics[i][:])
+	}
+	for i := range blooms {
+		for j := 0; j < 4; j++ {
+			blooms[i].AddValue(topics[rnd.Intn(len(topics))][:])
+		}
+	}
+	return blooms, topics[:3]
+}
+
+func BenchmarkBloomMatch(b *testing.B) {
+	blooms, topics := makeBenchBlooms(4096)
+	b.Run("big", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, bloom := range blooms {
+				for _, topic := range topics {
+					if BloomLookup(bloom, topic) {
+						break
+					}
+				}
+			}
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range blooms {
+				for _, topic := range topics {
+					if blooms[j].TestValue(topic[:]) {
+						break
+					}
+				}
+			}
+		}
+	})
+	b.Run("matcher", func(b *testing.B) {
+		group := make([][]byte, len(topics))
+		for i := range topics {
+			group[i] = topics[i][:]
+		}
+		for i := 0; i < b.N; i++ {
+			NewBloomMatcher([][][]byte{group}).MatchAll(blooms)
+		}
+	})
+}
+
+func BenchmarkBloomAdd(b *testing.B) {
+	value := common.Hash{1, 2, 3}
+	b.Run("big", func(b *testing.B) {
+		var bloom Bloom
+		for i := 0; i < b.N; i++ {
+			bloom.Add(value.Big())
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		var bloom Bloom
+		for i := 0; i < b.N; i++ {
+			bloom.AddValue(value[:])
+		}
+	})
+}
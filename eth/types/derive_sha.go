@@ -0,0 +1,304 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto/sha3"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// EmptyRootHash is the root hash of an empty trie, which is the TxHash and
+// ReceiptHash of blocks without transactions.
+var EmptyRootHash = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+// DerivableList is the input to DeriveSha. It is implemented by the
+// Transactions and Receipts types.
+type DerivableList interface {
+	Len() int
+	GetRlp(i int) []byte
+}
+
+// DeriveSha computes the root hash of the Merkle Patricia trie which maps
+// the RLP encoding of each list index i to GetRlp(i). This is how Ethereum
+// computes Header.TxHash and Header.ReceiptHash.
+//
+// The trie is built bottom-up from the sorted keys, keeping only the nodes
+// on the current path on the stack. No trie nodes are stored.
+func DeriveSha(list DerivableList) common.Hash {
+	return deriveSha(list, 1)
+}
+
+// DeriveShaParallel is like DeriveSha, but encodes the list elements and
+// hashes independent subtries on up to the given number of goroutines.
+func DeriveShaParallel(list DerivableList, workers int) common.Hash {
+	if workers < 1 {
+		workers = 1
+	}
+	return deriveSha(list, workers)
+}
+
+// DeriveRoots computes the transaction and receipt roots concurrently.
+func DeriveRoots(txs Transactions, receipts Receipts) (txHash, receiptHash common.Hash) {
+	done := make(chan struct{})
+	go func() {
+		receiptHash = DeriveSha(receipts)
+		close(done)
+	}()
+	txHash = DeriveSha(txs)
+	<-done
+	return txHash, receiptHash
+}
+
+// HashedTrieRoot returns the root hash of the trie mapping each key of
+// entries to its value. The keys are used as they are, so callers building
+// a secure trie, like the state and storage tries, must hash them first.
+// Entries with an empty value are absent from the trie.
+func HashedTrieRoot(entries map[common.Hash][]byte) common.Hash {
+	sorted := make([]trieEntry, 0, len(entries))
+	for key, value := range entries {
+		if len(value) > 0 {
+			key := key
+			sorted = append(sorted, trieEntry{keybytesToHex(key[:]), value})
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].key, sorted[j].key) < 0 })
+	return trieRoot(sorted, 1)
+}
+
+// trieEntry is a key/value pair of the trie. The key is stored as nibbles.
+type trieEntry struct {
+	key   []byte
+	value []byte
+}
+
+func deriveSha(list DerivableList, workers int) common.Hash {
+	return trieRoot(listEntries(list, workers), workers)
+}
+
+// listEntries returns the trie entries of list, sorted by key.
+func listEntries(list DerivableList, workers int) []trieEntry {
+	n := list.Len()
+	entries := make([]trieEntry, n)
+	encode := func(start, end int) {
+		for i := start; i < end; i++ {
+			key, _ := rlp.EncodeToBytes(uint(i))
+			entries[sortedIndex(i, n)] = trieEntry{keybytesToHex(key), list.GetRlp(i)}
+		}
+	}
+	if workers > 1 && n > workers {
+		common.ParallelWorker(n, workers, func(start, end int, _ ...interface{}) { encode(start, end) })
+	} else {
+		encode(0, n)
+	}
+	return entries
+}
+
+// sortedIndex returns the position of the key of list index i among the
+// sorted keys of a list of length n. The RLP encoding of 0 is 0x80, which
+// sorts after the single-byte encodings of 1 to 127.
+func sortedIndex(i, n int) int {
+	switch {
+	case i == 0:
+		if n > 128 {
+			return 127
+		}
+		return n - 1
+	case i < 128:
+		return i - 1
+	default:
+		return i
+	}
+}
+
+// trieRoot returns the root hash of the trie holding entries, which must be
+// sorted by key without duplicates.
+func trieRoot(entries []trieEntry, workers int) common.Hash {
+	if len(entries) == 0 {
+		return EmptyRootHash
+	}
+	h := &trieHasher{}
+	if workers > 1 {
+		h.sem = make(chan struct{}, workers-1)
+	}
+	return keccak(h.node(entries, 0, false))
+}
+
+// parallelThreshold is the minimum number of entries in a subtrie for it to
+// be hashed on a separate goroutine.
+const parallelThreshold = 256
+
+// trieHasher computes trie node encodings.
+type trieHasher struct {
+	sem chan struct{} // limits the number of extra goroutines, nil if sequential
+
+	// When target is set, the encodings of the nodes on the path to the
+	// target key are collected in path, deepest node first. The hasher
+	// must be sequential in that case.
+	target []byte
+	path   [][]byte
+}
+
+// node returns the encoding of the trie node holding entries, whose keys
+// all share the first depth nibbles. onPath reports whether the node is on
+// the path to the target key.
+func (h *trieHasher) node(entries []trieEntry, depth int, onPath bool) []byte {
+	if len(entries) == 1 {
+		return h.record(shortNode(entries[0].key[depth:], true, entries[0].value), onPath)
+	}
+	// The keys are sorted, so the common prefix of the first and last key
+	// is shared by all of them.
+	first, last := entries[0].key, entries[len(entries)-1].key
+	end := depth
+	for end < len(first) && end < len(last) && first[end] == last[end] {
+		end++
+	}
+	if end > depth {
+		child := nodeRef(h.branch(entries, end, onPath))
+		return h.record(shortNode(first[depth:end], false, child), onPath)
+	}
+	return h.branch(entries, depth, onPath)
+}
+
+// record adds a node on the target path to h.path.
+func (h *trieHasher) record(enc []byte, onPath bool) []byte {
+	if onPath && h.target != nil {
+		h.path = append(h.path, enc)
+	}
+	return enc
+}
+
+// branch returns the encoding of a branch node at the given depth.
+func (h *trieHasher) branch(entries []trieEntry, depth int, onPath bool) []byte {
+	// A key ending at this node sorts first and is stored as its value.
+	var value []byte
+	if len(entries[0].key) == depth {
+		value, entries = entries[0].value, entries[1:]
+	}
+	var children [16][]byte
+	var wg sync.WaitGroup
+	for nibble := byte(0); nibble < 16 && len(entries) > 0; nibble++ {
+		end := 0
+		for end < len(entries) && entries[end].key[depth] == nibble {
+			end++
+		}
+		if end == 0 {
+			continue
+		}
+		group, slot := entries[:end], &children[nibble]
+		entries = entries[end:]
+		childOnPath := onPath && h.target != nil && len(h.target) > depth && h.target[depth] == nibble
+		if h.sem != nil && len(group) >= parallelThreshold {
+			select {
+			case h.sem <- struct{}{}:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					*slot = nodeRef(h.node(group, depth+1, false))
+					<-h.sem
+				}()
+				continue
+			default:
+			}
+		}
+		*slot = nodeRef(h.node(group, depth+1, childOnPath))
+	}
+	wg.Wait()
+
+	w := rlp.NewEncoderBuffer(nil)
+	defer w.Flush()
+	list := w.List()
+	for _, child := range children {
+		if child == nil {
+			w.Write(rlp.EmptyString)
+		} else {
+			w.Write(child)
+		}
+	}
+	w.WriteBytes(value)
+	w.ListEnd(list)
+	return h.record(w.ToBytes(), onPath)
+}
+
+// shortNode returns the encoding of a leaf node holding value, or of an
+// extension node pointing to the node reference child.
+func shortNode(key []byte, leaf bool, val []byte) []byte {
+	w := rlp.NewEncoderBuffer(nil)
+	defer w.Flush()
+	list := w.List()
+	w.WriteBytes(hexToCompact(key, leaf))
+	if leaf {
+		w.WriteBytes(val)
+	} else {
+		w.Write(val)
+	}
+	w.ListEnd(list)
+	return w.ToBytes()
+}
+
+// nodeRef returns how a node is referenced from its parent: nodes shorter
+// than a hash are embedded, larger ones are referenced by their hash.
+func nodeRef(enc []byte) []byte {
+	if len(enc) < common.HashLength {
+		return enc
+	}
+	h := keccak(enc)
+	return append([]byte{0x80 + common.HashLength}, h[:]...)
+}
+
+func keccak(b []byte) (h common.Hash) {
+	hw := sha3.NewKeccak256()
+	hw.Write(b)
+	hw.Sum(h[:0])
+	return h
+}
+
+// keybytesToHex splits the bytes of key into nibbles.
+func keybytesToHex(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2)
+	for i, b := range key {
+		nibbles[i*2] = b / 16
+		nibbles[i*2+1] = b % 16
+	}
+	return nibbles
+}
+
+// hexToCompact packs nibbles into bytes using the hex-prefix encoding of
+// the yellow paper, which flags odd lengths and leaf nodes in the first
+// nibble.
+func hexToCompact(nibbles []byte, leaf bool) []byte {
+	var flag byte
+	if leaf {
+		flag = 2
+	}
+	buf := make([]byte, len(nibbles)/2+1)
+	if len(nibbles)%2 == 1 {
+		flag |= 1
+		buf[0] = flag<<4 | nibbles[0]
+		nibbles = nibbles[1:]
+	} else {
+		buf[0] = flag << 4
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		buf[i/2+1] = nibbles[i]<<4 | nibbles[i+1]
+	}
+	return buf
+}
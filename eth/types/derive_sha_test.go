@@ -0,0 +1,261 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// Test vectors from the trie tests of the Ethereum test suite.
+var trieRootTests = []struct {
+	name string
+	kv   map[string]string
+	root string
+}{
+	{"empty", map[string]string{}, "56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"},
+	{"dogs", map[string]string{"doe": "reindeer", "dog": "puppy", "dogglesworth": "cat"}, "8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3"},
+	{"puppy", map[string]string{"do": "verb", "horse": "stallion", "doge": "coin", "dog": "puppy"}, "5991bb8c6514148a29db676a14ac506cd2cd5775ace63c30a4fe457715e9ac84"},
+	{"foo", map[string]string{"foo": "bar", "food": "bass"}, "17beaa1648bafa633cda809c90c04af50fc8aed3cb40d16efbddee6fdf63c4c3"},
+	{"smallValues", map[string]string{"be": "e", "dog": "puppy", "bed": "d"}, "3f67c7a47520f79faa29255d2d3c084a7a6df0453116ed7232ff10277a8be68b"},
+	{"testy", map[string]string{"test": "test", "te": "testy"}, "8452568af70d8d140f58d941338542f645fcca50094b20f3c3d8c3df49337928"},
+}
+
+func TestTrieRoot(t *testing.T) {
+	for _, test := range trieRootTests {
+		var entries []trieEntry
+		for k, v := range test.kv {
+			entries = append(entries, trieEntry{keybytesToHex([]byte(k)), []byte(v)})
+		}
+		sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+		if root := trieRoot(entries, 1); root != common.HexToHash(test.root) {
+			t.Errorf("%s: got root %x, want %s", test.name, root, test.root)
+		}
+	}
+}
+
+func TestSortedIndex(t *testing.T) {
+	for _, n := range []int{1, 2, 127, 128, 129, 300, 70000} {
+		keys := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			key, _ := rlp.EncodeToBytes(uint(i))
+			keys[sortedIndex(i, n)] = key
+		}
+		for i := 1; i < n; i++ {
+			if bytes.Compare(keys[i-1], keys[i]) >= 0 {
+				t.Fatalf("n=%d: keys at %d and %d are not sorted: %x %x", n, i-1, i, keys[i-1], keys[i])
+			}
+		}
+	}
+}
+
+// byteList is a DerivableList of arbitrary values.
+type byteList [][]byte
+
+func (l byteList) Len() int            { return len(l) }
+func (l byteList) GetRlp(i int) []byte { return l[i] }
+
+func makeByteList(n int) byteList {
+	list := make(byteList, n)
+	for i := range list {
+		list[i] = []byte(fmt.Sprintf("value %d", i*i))
+	}
+	return list
+}
+
+func TestDeriveSha(t *testing.T) {
+	if root := DeriveSha(Transactions{}); root != EmptyRootHash {
+		t.Errorf("wrong root of empty list: %x", root)
+	}
+	// A single element is stored in a leaf under the key rlp(0) = 0x80.
+	list := byteList{[]byte("a value")}
+	want := keccak(shortNode([]byte{8, 0}, true, list[0]))
+	if root := DeriveSha(list); root != want {
+		t.Errorf("wrong root of single element: %x, want %x", root, want)
+	}
+	// The list root must equal the trie root of the explicit key/value pairs.
+	for _, n := range []int{2, 16, 127, 128, 129, 1000} {
+		list := makeByteList(n)
+		entries := make([]trieEntry, n)
+		for i := range list {
+			key, _ := rlp.EncodeToBytes(uint(i))
+			entries[i] = trieEntry{keybytesToHex(key), list[i]}
+		}
+		sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+		if root, want := DeriveSha(list), trieRoot(entries, 1); root != want {
+			t.Errorf("n=%d: got root %x, want %x", n, root, want)
+		}
+	}
+}
+
+func TestDeriveShaParallel(t *testing.T) {
+	for _, n := range []int{1, 10, 300, 5000} {
+		list := makeByteList(n)
+		want := DeriveSha(list)
+		for _, workers := range []int{0, 2, 8} {
+			if root := DeriveShaParallel(list, workers); root != want {
+				t.Errorf("n=%d workers=%d: got root %x, want %x", n, workers, root, want)
+			}
+		}
+	}
+}
+
+func TestDeriveRoots(t *testing.T) {
+	_, _, txs := testSignedTxs(t)
+	receipts := Receipts{
+		{Status: ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*Log{{Address: testAddr, Topics: []common.Hash{{1}}, Data: []byte{1}}}},
+		{Type: DynamicFeeTxType, Status: ReceiptStatusFailed, CumulativeGasUsed: 50000},
+	}
+	txHash, receiptHash := DeriveRoots(txs, receipts)
+	if txHash != DeriveSha(Transactions(txs)) || receiptHash != DeriveSha(receipts) {
+		t.Errorf("DeriveRoots mismatch")
+	}
+	// Typed transactions and receipts are keyed by their type-prefixed encoding.
+	if enc := Transactions(txs).GetRlp(3); enc[0] != DynamicFeeTxType {
+		t.Errorf("typed transaction encoding not prefixed: %x", enc[:4])
+	}
+	if enc := receipts.GetRlp(1); enc[0] != DynamicFeeTxType {
+		t.Errorf("typed receipt encoding not prefixed: %x", enc[:4])
+	}
+	var dec struct {
+		Status            []byte
+		CumulativeGasUsed uint64
+		Bloom             Bloom
+		Logs              []*Log
+	}
+	if err := rlp.DecodeBytes(receipts.GetRlp(0), &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.CumulativeGasUsed != 21000 || len(dec.Logs) != 1 || !bytes.Equal(dec.Status, []byte{1}) {
+		t.Errorf("wrong consensus encoding: %+v", dec)
+	}
+}
+
+// A block of the BlockchainTests fixtures of the Ethereum test suite, which
+// go-ethereum also uses in TestBlockEncoding. Its hash is checked below, so
+// the transaction root in its header is authentic.
+var fixtureBlockRLP = "f90260f901f9a083cafc574e1f51ba9dc0568fc617a08ea2429fb384059c972f13b19fa1c8dd55a01dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347948888f1f195afa192cfee860698584c030f4c9db1a0ef1552a40b7165c3cd773806b9e0c165b75356e0314bf0706f279c729f51e017a05fe50b260da6308036625b850b5d6ced6d0a9f814c0688bc91ffb7b7a3a54b67a0bc37d79753ad738a6dac4921e57392f145d8887476de3f783dfa7edae9283e52b90100" + strings.Repeat("00", BloomByteLength) + "8302000001832fefd8825208845506eb0780a0bd4472abb6659ebe3ee06ee4d7b72a00a9f4d001caca51342001075469aff49888a13a5a8c8f2bb1c4f861f85f800a82c35094095e7baea6a6c7c4c2dfeb977efac326af552d870a801ba09bea4c4daac7c7c52e093e6a4c35dbbcf8856f1af7b059ba20253e70848d094fa08a8fae537ce25ed8cb5af9adac3f141af69bd515bd2ba031522df09b97dd72b1c0"
+
+func TestDeriveShaFixtureBlock(t *testing.T) {
+	var block Block
+	if err := rlp.DecodeBytes(common.FromHex(fixtureBlockRLP), &block); err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0a5843ac1cb04865017cb35a57b50b07084e5fcee39b5acadade33149f4fff9e"); block.Hash() != want {
+		t.Fatalf("wrong block hash %x, want %x", block.Hash(), want)
+	}
+	if root := DeriveSha(block.Transactions()); root != block.TxHash() {
+		t.Errorf("got transaction root %x, want %x", root, block.TxHash())
+	}
+	if root := DeriveShaParallel(block.Transactions(), 4); root != block.TxHash() {
+		t.Errorf("got parallel transaction root %x, want %x", root, block.TxHash())
+	}
+}
+
+// Block 8651337 of the Goerli network as returned by eth_getBlockByHash with
+// full transactions, taken from the Optimism source fetcher tests. Its 62
+// legacy and dynamic fee transactions span branch and extension nodes.
+func TestDeriveShaGoerliBlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/goerli_block_8651337.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rb RPCBlock
+	if err := json.Unmarshal(data, &rb); err != nil {
+		t.Fatal(err)
+	}
+	// Block checks the derived transaction root against the header and the
+	// header hash against the one of the response.
+	block, err := rb.Block()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0x9ef7cd2241202b919a0e51240818a8666c73f7ce4b908931e3ae6d26d30f7663"); block.Hash() != want {
+		t.Fatalf("wrong block hash %x, want %x", block.Hash(), want)
+	}
+	txs := block.Transactions()
+	want := common.HexToHash("0x1ad3212eca045505cfc4cacf675b5fa2e7dc7b9f9cee88191464f97d1c9fbca4")
+	if root := DeriveSha(txs); root != want {
+		t.Errorf("got transaction root %x, want %x", root, want)
+	}
+	if root := DeriveShaParallel(txs, 4); root != want {
+		t.Errorf("got parallel transaction root %x, want %x", root, want)
+	}
+}
+
+// singleLeafRoot computes the root of a list trie holding one value by hand:
+// a leaf node with the hex-prefix encoded key rlp(0) = 0x80.
+func singleLeafRoot(value []byte) common.Hash {
+	enc, _ := rlp.EncodeToBytes([][]byte{{0x20, 0x80}, value})
+	return keccak(enc)
+}
+
+// Typed transactions and receipts are stored in the trie with their EIP-2718
+// envelope, the type byte followed by the RLP payload.
+func TestDeriveShaTyped(t *testing.T) {
+	// Envelope of the access list transaction of TestEIP2718TransactionEncode.
+	envelope := common.FromHex("01f8630103018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001a0c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521")
+	txs := Transactions{signedEip2718Tx}
+	if enc := txs.GetRlp(0); !bytes.Equal(enc, envelope) {
+		t.Fatalf("wrong transaction trie value\ngot  %x\nwant %x", enc, envelope)
+	}
+	if root, want := DeriveSha(txs), singleLeafRoot(envelope); root != want {
+		t.Errorf("got transaction root %x, want %x", root, want)
+	}
+
+	receipt := &Receipt{Type: DynamicFeeTxType, Status: ReceiptStatusSuccessful, CumulativeGasUsed: 0x62d4}
+	payload, _ := rlp.EncodeToBytes([]interface{}{[]byte{1}, uint64(0x62d4), make([]byte, BloomByteLength), []interface{}{}})
+	envelope = append([]byte{DynamicFeeTxType}, payload...)
+	receipts := Receipts{receipt}
+	if enc := receipts.GetRlp(0); !bytes.Equal(enc, envelope) {
+		t.Fatalf("wrong receipt trie value\ngot  %x\nwant %x", enc, envelope)
+	}
+	if root, want := DeriveSha(receipts), singleLeafRoot(envelope); root != want {
+		t.Errorf("got receipt root %x, want %x", root, want)
+	}
+	// Legacy receipts have no envelope.
+	receipt.Type = LegacyTxType
+	if root, want := DeriveSha(receipts), singleLeafRoot(payload); root != want {
+		t.Errorf("got legacy receipt root %x, want %x", root, want)
+	}
+}
+
+func BenchmarkDeriveSha(b *testing.B) {
+	txs := make(Transactions, 2000)
+	for i := range txs {
+		txs[i] = NewTransaction(uint64(i), testAddr, big.NewInt(int64(i)), 21000, big.NewInt(1), nil)
+	}
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			DeriveSha(txs)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			DeriveShaParallel(txs, 8)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/params"
+)
+
+// maxGasLimit is the largest gas limit a header may have, which keeps gas
+// arithmetic within int64.
+const maxGasLimit = 0x7fffffffffffffff
+
+var (
+	// ErrUnknownParent is returned if the parent hash of a header does not
+	// match the hash of the preceding header.
+	ErrUnknownParent = errors.New("unknown parent")
+
+	// ErrInvalidNumber is returned if the number of a header does not
+	// follow the number of its parent.
+	ErrInvalidNumber = errors.New("invalid block number")
+
+	// ErrOlderBlockTime is returned if the timestamp of a header is not
+	// above the timestamp of its parent.
+	ErrOlderBlockTime = errors.New("timestamp older than parent")
+
+	// ErrInvalidGasUsed is returned if a header uses more gas than its
+	// gas limit.
+	ErrInvalidGasUsed = errors.New("invalid gas used")
+
+	// ErrInvalidGasLimit is returned if the gas limit of a header is out of
+	// range or changes too much from the gas limit of its parent.
+	ErrInvalidGasLimit = errors.New("invalid gas limit")
+
+	// ErrExtraDataTooLong is returned if the extra-data of a header exceeds
+	// params.MaximumExtraDataSize.
+	ErrExtraDataTooLong = errors.New("extra-data too long")
+)
+
+// HeaderError is returned by ValidateHeaderChain for the first header that
+// fails validation.
+type HeaderError struct {
+	Index  int         // position of the header in the validated chain
+	Number *big.Int    // number of the header
+	Hash   common.Hash // hash of the header
+	Err    error       // reason, e.g. ErrInvalidGasLimit
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("invalid header %d (number %v, hash %x): %v", e.Index, e.Number, e.Hash, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *HeaderError) Unwrap() error { return e.Err }
+
+// ValidateHeader checks the fields of header which do not depend on other
+// headers: the gas used must not exceed the gas limit, and the extra-data
+// of headers after genesis is limited to params.MaximumExtraDataSize bytes.
+func ValidateHeader(header *Header) error {
+	if header.Number.Sign() > 0 && uint64(len(header.Extra)) > params.MaximumExtraDataSize {
+		return fmt.Errorf("%w: have %d, want <= %d", ErrExtraDataTooLong, len(header.Extra), params.MaximumExtraDataSize)
+	}
+	if header.GasLimit > maxGasLimit {
+		return fmt.Errorf("%w: have %d, want <= %d", ErrInvalidGasLimit, header.GasLimit, uint64(maxGasLimit))
+	}
+	if header.GasUsed > header.GasLimit {
+		return fmt.Errorf("%w: have %d, gas limit %d", ErrInvalidGasUsed, header.GasUsed, header.GasLimit)
+	}
+	return nil
+}
+
+// ValidateHeaderLink checks that header is a valid child of parent. Besides
+// the checks of ValidateHeader, the parent hash must match, the number must
+// be one above the parent's, the timestamp must be above the parent's, and
+// the gas limit may change by less than 1/params.GasLimitBoundDivisor of the
+// parent's gas limit, staying at or above params.MinGasLimit.
+func ValidateHeaderLink(parent, header *Header) error {
+	if err := ValidateHeader(header); err != nil {
+		return err
+	}
+	if hash := parent.Hash(); header.ParentHash != hash {
+		return fmt.Errorf("%w: have %x, want %x", ErrUnknownParent, header.ParentHash, hash)
+	}
+	if diff := new(big.Int).Sub(header.Number, parent.Number); diff.Cmp(common.Big1) != 0 {
+		return fmt.Errorf("%w: have %v, parent %v", ErrInvalidNumber, header.Number, parent.Number)
+	}
+	if header.Time.Cmp(parent.Time) <= 0 {
+		return fmt.Errorf("%w: have %v, parent %v", ErrOlderBlockTime, header.Time, parent.Time)
+	}
+	diff := int64(parent.GasLimit) - int64(header.GasLimit)
+	if diff < 0 {
+		diff = -diff
+	}
+	limit := parent.GasLimit / params.GasLimitBoundDivisor
+	if uint64(diff) >= limit || header.GasLimit < params.MinGasLimit {
+		return fmt.Errorf("%w: have %d, want %d += %d", ErrInvalidGasLimit, header.GasLimit, parent.GasLimit, limit-1)
+	}
+	return nil
+}
+
+// ValidateHeaderChain checks that headers form a contiguous chain, each
+// header being a valid child of the one before it according to
+// ValidateHeaderLink. The first header is only checked with ValidateHeader.
+// The failing header is reported as a *HeaderError.
+func ValidateHeaderChain(headers []*Header) error {
+	for i, header := range headers {
+		var err error
+		if i == 0 {
+			err = ValidateHeader(header)
+		} else {
+			err = ValidateHeaderLink(headers[i-1], header)
+		}
+		if err != nil {
+			return &HeaderError{Index: i, Number: header.Number, Hash: header.Hash(), Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/params"
+)
+
+// makeHeaderChain creates a valid chain of n headers starting at genesis.
+func makeHeaderChain(n int) []*Header {
+	headers := make([]*Header, n)
+	for i := range headers {
+		headers[i] = &Header{
+			Number:     big.NewInt(int64(i)),
+			Time:       big.NewInt(int64(10 * i)),
+			Difficulty: big.NewInt(1),
+			GasLimit:   params.GenesisGasLimit,
+			GasUsed:    21000,
+		}
+	}
+	headers[0].Extra = make([]byte, 64)
+	relinkHeaders(headers)
+	return headers
+}
+
+// relinkHeaders sets the parent hashes of headers after modifications.
+func relinkHeaders(headers []*Header) {
+	for i := 1; i < len(headers); i++ {
+		headers[i].ParentHash = headers[i-1].Hash()
+	}
+}
+
+func TestValidateHeaderChain(t *testing.T) {
+	if err := ValidateHeaderChain(makeHeaderChain(5)); err != nil {
+		t.Fatalf("valid chain rejected: %v", err)
+	}
+	if err := ValidateHeaderChain(nil); err != nil {
+		t.Fatalf("empty chain rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func([]*Header)
+		relink bool
+		index  int
+		err    error
+	}{
+		{"parent hash", func(h []*Header) { h[2].ParentHash = common.Hash{1} }, false, 2, ErrUnknownParent},
+		{"stale parent", func(h []*Header) { h[1].GasUsed = 0 }, false, 2, ErrUnknownParent},
+		{"number gap", func(h []*Header) { h[3].Number = big.NewInt(4); h[4].Number = big.NewInt(5) }, true, 3, ErrInvalidNumber},
+		{"number repeat", func(h []*Header) { h[2].Number = big.NewInt(1) }, true, 2, ErrInvalidNumber},
+		{"timestamp equal", func(h []*Header) { h[4].Time = big.NewInt(30) }, true, 4, ErrOlderBlockTime},
+		{"timestamp older", func(h []*Header) { h[1].Time = big.NewInt(25) }, true, 2, ErrOlderBlockTime},
+		{"gas used", func(h []*Header) { h[3].GasUsed = h[3].GasLimit + 1 }, true, 3, ErrInvalidGasUsed},
+		{"gas used first", func(h []*Header) { h[0].GasLimit = 0 }, true, 0, ErrInvalidGasUsed},
+		{"gas limit raise", func(h []*Header) { h[1].GasLimit += params.GenesisGasLimit / params.GasLimitBoundDivisor }, true, 1, ErrInvalidGasLimit},
+		{"gas limit drop", func(h []*Header) { h[1].GasLimit -= params.GenesisGasLimit / params.GasLimitBoundDivisor }, true, 1, ErrInvalidGasLimit},
+		{"gas limit max", func(h []*Header) { h[1].GasLimit = 1 << 63 }, true, 1, ErrInvalidGasLimit},
+		{"extra-data", func(h []*Header) { h[1].Extra = make([]byte, params.MaximumExtraDataSize+1) }, true, 1, ErrExtraDataTooLong},
+	}
+	for _, test := range tests {
+		headers := makeHeaderChain(5)
+		test.modify(headers)
+		if test.relink {
+			relinkHeaders(headers)
+		}
+		err := ValidateHeaderChain(headers)
+		var herr *HeaderError
+		if !errors.As(err, &herr) {
+			t.Errorf("%s: got error %v, want *HeaderError", test.name, err)
+			continue
+		}
+		if herr.Index != test.index || herr.Hash != headers[test.index].Hash() || !errors.Is(err, test.err) {
+			t.Errorf("%s: got %v, want %v at index %d", test.name, err, test.err, test.index)
+		}
+	}
+
+	// The gas limit may move by just under the bound.
+	headers := makeHeaderChain(3)
+	headers[1].GasLimit += params.GenesisGasLimit/params.GasLimitBoundDivisor - 1
+	headers[2].GasLimit = headers[1].GasLimit - (headers[1].GasLimit/params.GasLimitBoundDivisor - 1)
+	relinkHeaders(headers)
+	if err := ValidateHeaderChain(headers); err != nil {
+		t.Errorf("gas limit within bounds rejected: %v", err)
+	}
+}
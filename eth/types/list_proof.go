@@ -0,0 +1,228 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+var (
+	// ErrInvalidProof is returned when a Merkle proof does not prove the
+	// presence of its element under the expected root.
+	ErrInvalidProof = errors.New("invalid merkle proof")
+
+	errProofIndex = errors.New("proof index out of range")
+)
+
+// ListProof is a Merkle proof for a single element of a DerivableList
+// against the root computed by DeriveSha, e.g. for a transaction against
+// Header.TxHash or a receipt against Header.ReceiptHash.
+//
+// Nodes holds the encodings of the trie nodes on the path from the root to
+// the element, root first. Nodes small enough to be embedded in their
+// parent are not repeated. The RLP encoding of a ListProof is its compact
+// serialized form, see MarshalBinary.
+type ListProof struct {
+	Index uint64
+	Nodes [][]byte
+}
+
+// ProveListElement creates a proof for the element at the given index. It
+// rebuilds the trie of the whole list.
+func ProveListElement(list DerivableList, index int) (*ListProof, error) {
+	if index < 0 || index >= list.Len() {
+		return nil, errProofIndex
+	}
+	key, _ := rlp.EncodeToBytes(uint(index))
+	h := &trieHasher{target: keybytesToHex(key)}
+	h.node(listEntries(list, 1), 0, true)
+
+	// The path was collected bottom-up. Only the root and nodes referenced
+	// by hash are proof nodes, smaller ones are part of their parent.
+	proof := &ListProof{Index: uint64(index)}
+	for i := len(h.path) - 1; i >= 0; i-- {
+		if i == len(h.path)-1 || len(h.path[i]) >= common.HashLength {
+			proof.Nodes = append(proof.Nodes, h.path[i])
+		}
+	}
+	return proof, nil
+}
+
+// ProveTransaction creates a proof for the transaction at the given index
+// against the transaction root of txs.
+func ProveTransaction(txs Transactions, index int) (*ListProof, error) {
+	return ProveListElement(txs, index)
+}
+
+// ProveReceipt creates a proof for the receipt at the given index against
+// the receipt root of receipts.
+func ProveReceipt(receipts Receipts, index int) (*ListProof, error) {
+	return ProveListElement(receipts, index)
+}
+
+// MarshalBinary encodes the proof as the RLP list [index, [node, ...]].
+func (p *ListProof) MarshalBinary() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// UnmarshalBinary decodes a proof created by MarshalBinary.
+func (p *ListProof) UnmarshalBinary(b []byte) error {
+	return rlp.DecodeBytes(b, p)
+}
+
+// Verify checks the proof against root and returns the proven element,
+// i.e. the value of GetRlp for the element's index.
+func (p *ListProof) Verify(root common.Hash) ([]byte, error) {
+	nodes := make(map[common.Hash][]byte, len(p.Nodes))
+	for _, n := range p.Nodes {
+		nodes[keccak(n)] = n
+	}
+	key, _ := rlp.EncodeToBytes(uint(p.Index))
+	nibbles := keybytesToHex(key)
+
+	enc, ok := nodes[root]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing root node %x", ErrInvalidProof, root)
+	}
+	for {
+		elems, err := splitNode(enc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		}
+		var ref []byte
+		switch len(elems) {
+		case 2:
+			prefix, leaf := compactToHex(elems[0])
+			if !bytes.HasPrefix(nibbles, prefix) {
+				return nil, fmt.Errorf("%w: key not in trie", ErrInvalidProof)
+			}
+			nibbles = nibbles[len(prefix):]
+			if leaf {
+				if len(nibbles) != 0 {
+					return nil, fmt.Errorf("%w: key not in trie", ErrInvalidProof)
+				}
+				return proofValue(elems[1])
+			}
+			ref = elems[1]
+		case 17:
+			if len(nibbles) == 0 {
+				return proofValue(elems[16])
+			}
+			ref, nibbles = elems[nibbles[0]], nibbles[1:]
+		default:
+			return nil, fmt.Errorf("%w: invalid node with %d elements", ErrInvalidProof, len(elems))
+		}
+		// Resolve the child reference.
+		kind, content, _, err := rlp.Split(ref)
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		case kind == rlp.List:
+			enc = ref
+		case len(content) == common.HashLength:
+			if enc, ok = nodes[common.BytesToHash(content)]; !ok {
+				return nil, fmt.Errorf("%w: missing node %x", ErrInvalidProof, content)
+			}
+		default:
+			return nil, fmt.Errorf("%w: key not in trie", ErrInvalidProof)
+		}
+	}
+}
+
+// proofValue returns the content of a value in a trie node.
+func proofValue(enc []byte) ([]byte, error) {
+	value, _, err := rlp.SplitString(enc)
+	if err == nil && len(value) == 0 {
+		err = errors.New("empty value")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return value, nil
+}
+
+// splitNode returns the encoded elements of a trie node.
+func splitNode(enc []byte) ([][]byte, error) {
+	content, rest, err := rlp.SplitList(enc)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, rlp.ErrMoreThanOneValue
+	}
+	var elems [][]byte
+	for len(content) > 0 {
+		_, _, next, err := rlp.Split(content)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, content[:len(content)-len(next)])
+		content = next
+	}
+	return elems, nil
+}
+
+// compactToHex decodes an RLP string holding a hex-prefix encoded key
+// into nibbles, reporting whether it is the key of a leaf.
+func compactToHex(enc []byte) (nibbles []byte, leaf bool) {
+	compact, _, err := rlp.SplitString(enc)
+	if err != nil || len(compact) == 0 {
+		return nil, false
+	}
+	flag := compact[0] >> 4
+	nibbles = keybytesToHex(compact)
+	if flag&1 == 1 {
+		nibbles = nibbles[1:]
+	} else {
+		nibbles = nibbles[2:]
+	}
+	return nibbles, flag&2 == 2
+}
+
+// VerifyTransactionProof checks a transaction proof against a block's
+// transaction root and returns the proven transaction.
+func VerifyTransactionProof(txHash common.Hash, proof *ListProof) (*Transaction, error) {
+	enc, err := proof.Verify(txHash)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(Transaction)
+	if err := tx.UnmarshalBinary(enc); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// VerifyReceiptProof checks a receipt proof against a block's receipt root
+// and returns the proven receipt. Only the consensus fields of the receipt
+// are set.
+func VerifyReceiptProof(receiptHash common.Hash, proof *ListProof) (*Receipt, error) {
+	enc, err := proof.Verify(receiptHash)
+	if err != nil {
+		return nil, err
+	}
+	r := new(Receipt)
+	if err := r.decodeConsensus(enc); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
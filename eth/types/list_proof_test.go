@@ -0,0 +1,114 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+func makeTestTxs(n int) Transactions {
+	txs := make(Transactions, n)
+	for i := range txs {
+		txs[i] = NewTransaction(uint64(i), testAddr, big.NewInt(int64(i)), 21000, big.NewInt(1), make([]byte, i%50))
+	}
+	return txs
+}
+
+func TestTransactionProof(t *testing.T) {
+	for _, n := range []int{1, 2, 16, 130, 300} {
+		txs := makeTestTxs(n)
+		root := DeriveSha(txs)
+		for i := 0; i < n; i += 1 + n/20 {
+			proof, err := ProveTransaction(txs, i)
+			if err != nil {
+				t.Fatalf("n=%d i=%d: %v", n, i, err)
+			}
+			// Roundtrip through the serialized form.
+			enc, err := proof.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dec := new(ListProof)
+			if err := dec.UnmarshalBinary(enc); err != nil {
+				t.Fatal(err)
+			}
+			tx, err := VerifyTransactionProof(root, dec)
+			if err != nil {
+				t.Fatalf("n=%d i=%d: verification failed: %v", n, i, err)
+			}
+			if tx.Hash() != txs[i].Hash() {
+				t.Errorf("n=%d i=%d: proved wrong transaction", n, i)
+			}
+		}
+	}
+	if _, err := ProveTransaction(makeTestTxs(3), 3); err != errProofIndex {
+		t.Errorf("wrong error for index out of range: %v", err)
+	}
+}
+
+func TestReceiptProof(t *testing.T) {
+	receipts := make(Receipts, 40)
+	for i := range receipts {
+		receipts[i] = &Receipt{Status: uint64(i % 2), CumulativeGasUsed: uint64(21000 * (i + 1))}
+		if i%3 == 0 {
+			receipts[i].Type = DynamicFeeTxType
+			receipts[i].Logs = []*Log{{Address: testAddr, Topics: []common.Hash{{byte(i)}}, Data: []byte{byte(i)}}}
+		}
+	}
+	root := DeriveSha(receipts)
+	for i, want := range receipts {
+		proof, err := ProveReceipt(receipts, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := VerifyReceiptProof(root, proof)
+		if err != nil {
+			t.Fatalf("receipt %d: verification failed: %v", i, err)
+		}
+		if r.Type != want.Type || r.Status != want.Status || r.CumulativeGasUsed != want.CumulativeGasUsed || len(r.Logs) != len(want.Logs) {
+			t.Errorf("receipt %d: got %+v, want %+v", i, r, want)
+		}
+	}
+}
+
+func TestInvalidProof(t *testing.T) {
+	txs := makeTestTxs(200)
+	root := DeriveSha(txs)
+	proof, _ := ProveTransaction(txs, 150)
+
+	check := func(name string, root common.Hash, p *ListProof) {
+		t.Helper()
+		if _, err := p.Verify(root); !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: got error %v, want ErrInvalidProof", name, err)
+		}
+	}
+	check("wrong root", common.Hash{1}, proof)
+	check("wrong index", root, &ListProof{Index: 151, Nodes: proof.Nodes})
+	check("missing node", root, &ListProof{Index: 150, Nodes: proof.Nodes[:len(proof.Nodes)-1]})
+
+	tampered := &ListProof{Index: 150}
+	for _, n := range proof.Nodes {
+		tampered.Nodes = append(tampered.Nodes, append([]byte{}, n...))
+	}
+	last := tampered.Nodes[len(tampered.Nodes)-1]
+	last[len(last)-1]++
+	check("tampered node", root, tampered)
+}
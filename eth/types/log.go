@@ -81,7 +81,22 @@ type rlpStorageLog struct {
 
 // EncodeRLP implements rlp.Encoder.
 func (l *Log) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, rlpLog{Address: l.Address, Topics: l.Topics, Data: l.Data})
+	buf := rlp.NewEncoderBuffer(w)
+	list := buf.List()
+	l.encodeConsensus(buf)
+	buf.ListEnd(list)
+	return buf.Flush()
+}
+
+// encodeConsensus writes the consensus fields of the log to buf.
+func (l *Log) encodeConsensus(buf rlp.EncoderBuffer) {
+	buf.WriteBytes(l.Address[:])
+	topics := buf.List()
+	for i := range l.Topics {
+		buf.WriteBytes(l.Topics[i][:])
+	}
+	buf.ListEnd(topics)
+	buf.WriteBytes(l.Data)
 }
 
 // DecodeRLP implements rlp.Decoder.
@@ -100,16 +115,16 @@ type LogForStorage Log
 
 // EncodeRLP implements rlp.Encoder.
 func (l *LogForStorage) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, rlpStorageLog{
-		Address:     l.Address,
-		Topics:      l.Topics,
-		Data:        l.Data,
-		BlockNumber: l.BlockNumber,
-		TxHash:      l.TxHash,
-		TxIndex:     l.TxIndex,
-		BlockHash:   l.BlockHash,
-		Index:       l.Index,
-	})
+	buf := rlp.NewEncoderBuffer(w)
+	list := buf.List()
+	(*Log)(l).encodeConsensus(buf)
+	buf.WriteUint64(l.BlockNumber)
+	buf.WriteBytes(l.TxHash[:])
+	buf.WriteUint64(uint64(l.TxIndex))
+	buf.WriteBytes(l.BlockHash[:])
+	buf.WriteUint64(uint64(l.Index))
+	buf.ListEnd(list)
+	return buf.Flush()
 }
 
 // DecodeRLP implements rlp.Decoder.
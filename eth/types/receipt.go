@@ -18,6 +18,7 @@ package types
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -25,11 +26,15 @@ import (
 
 	"github.com/arcology-network/3rd-party/eth/common"
 	"github.com/arcology-network/3rd-party/eth/common/hexutil"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+	"github.com/arcology-network/3rd-party/eth/params"
 	"github.com/arcology-network/3rd-party/eth/rlp"
 )
 
 //go:generate gencodec -type Receipt -field-override receiptMarshaling -out gen_receipt_json.go
 
+var errReceiptCount = errors.New("transaction and receipt count mismatch")
+
 var (
 	receiptStatusFailedRLP     = []byte{}
 	receiptStatusSuccessfulRLP = []byte{0x01}
@@ -67,10 +72,13 @@ type Receipt struct {
 }
 
 type receiptMarshaling struct {
+	Type              hexutil.Uint64
 	PostState         hexutil.Bytes
 	Status            hexutil.Uint64
 	CumulativeGasUsed hexutil.Uint64
 	GasUsed           hexutil.Uint64
+	BlockNumber       *hexutil.Big
+	TransactionIndex  hexutil.Uint
 }
 
 // receiptRLP is the consensus encoding of a receipt.
@@ -133,6 +141,58 @@ func (r *Receipt) DecodeRLP(s *rlp.Stream) error {
 	return nil
 }
 
+// consensusEncoding returns the canonical Ethereum encoding of the receipt:
+// the RLP list of status, cumulative gas used, bloom and logs, prefixed with
+// the receipt type for typed receipts.
+func (r *Receipt) consensusEncoding() []byte {
+	w := rlp.NewEncoderBuffer(nil)
+	defer w.Flush()
+	list := w.List()
+	w.WriteBytes(r.statusEncoding())
+	w.WriteUint64(r.CumulativeGasUsed)
+	w.WriteBytes(r.Bloom[:])
+	logs := w.List()
+	for _, log := range r.Logs {
+		l := w.List()
+		log.encodeConsensus(w)
+		w.ListEnd(l)
+	}
+	w.ListEnd(logs)
+	w.ListEnd(list)
+	enc := w.ToBytes()
+	if r.Type == LegacyTxType {
+		return enc
+	}
+	return append([]byte{r.Type}, enc...)
+}
+
+// receiptConsensusRLP is the consensus encoding of a receipt.
+type receiptConsensusRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             Bloom
+	Logs              []*Log
+}
+
+// decodeConsensus parses the encoding created by consensusEncoding.
+func (r *Receipt) decodeConsensus(b []byte) error {
+	if len(b) > 0 && b[0] <= 0x7f {
+		if b[0] != AccessListTxType && b[0] != DynamicFeeTxType {
+			return ErrTxTypeNotSupported
+		}
+		r.Type, b = b[0], b[1:]
+	}
+	var dec receiptConsensusRLP
+	if err := rlp.DecodeBytes(b, &dec); err != nil {
+		return err
+	}
+	if err := r.setStatus(dec.PostStateOrStatus); err != nil {
+		return err
+	}
+	r.CumulativeGasUsed, r.Bloom, r.Logs = dec.CumulativeGasUsed, dec.Bloom, dec.Logs
+	return nil
+}
+
 func (r *Receipt) setStatus(postStateOrStatus []byte) error {
 	switch {
 	case bytes.Equal(postStateOrStatus, receiptStatusSuccessfulRLP):
@@ -219,11 +279,61 @@ type Receipts []*Receipt
 // Len returns the number of receipts in this list.
 func (r Receipts) Len() int { return len(r) }
 
-// GetRlp returns the RLP encoding of one receipt from the list.
+// GetRlp returns the consensus encoding of one receipt from the list, which
+// is what Ethereum hashes into Header.ReceiptHash. Note that this differs
+// from the RLP encoding produced by Receipt.EncodeRLP, which includes the
+// implementation fields.
 func (r Receipts) GetRlp(i int) []byte {
-	bytes, err := rlp.EncodeToBytes(r[i])
-	if err != nil {
-		panic(err)
+	return r[i].consensusEncoding()
+}
+
+// DeriveFields fills the receipts with their computed fields based on consensus
+// data and contextual infos like containing block and transactions: the type,
+// transaction hash and position of each receipt, the gas used, which is the
+// difference of consecutive cumulative gas used values, the address of created
+// contracts and the derived fields of the logs. It is needed for receipts
+// decoded from their consensus or storage encoding, which omit these fields.
+func (r Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, number uint64, txs Transactions) error {
+	if len(txs) != len(r) {
+		return errReceiptCount
 	}
-	return bytes
+	signer := MakeSigner(config, new(big.Int).SetUint64(number))
+
+	logIndex := uint(0)
+	for i, receipt := range r {
+		receipt.Type = txs[i].Type()
+		receipt.TxHash = txs[i].Hash()
+		receipt.BlockHash = hash
+		receipt.BlockNumber = new(big.Int).SetUint64(number)
+		receipt.TransactionIndex = uint(i)
+
+		// The contract address can be derived from the transaction itself
+		if txs[i].To() == nil {
+			from, err := Sender(signer, txs[i])
+			if err != nil {
+				return fmt.Errorf("receipt %d: %v", i, err)
+			}
+			receipt.ContractAddress = crypto.CreateAddress(from, txs[i].Nonce())
+		} else {
+			receipt.ContractAddress = common.Address{}
+		}
+		// The used gas can be calculated based on previous receipt
+		if i == 0 {
+			receipt.GasUsed = receipt.CumulativeGasUsed
+		} else if prev := r[i-1].CumulativeGasUsed; receipt.CumulativeGasUsed < prev {
+			return fmt.Errorf("receipt %d: cumulative gas used %d below previous %d", i, receipt.CumulativeGasUsed, prev)
+		} else {
+			receipt.GasUsed = receipt.CumulativeGasUsed - prev
+		}
+		// The derived log fields can simply be set from the block and transaction
+		for _, log := range receipt.Logs {
+			log.BlockNumber = number
+			log.BlockHash = hash
+			log.TxHash = receipt.TxHash
+			log.TxIndex = uint(i)
+			log.Index = logIndex
+			logIndex++
+		}
+	}
+	return nil
 }
@@ -0,0 +1,128 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+	"github.com/arcology-network/3rd-party/eth/params"
+	"github.com/arcology-network/3rd-party/eth/rlp"
+)
+
+// Tests that receipt data can be correctly derived from the contextual infos
+// after a round trip through the storage encoding.
+func TestDeriveFields(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	signer := LatestSigner(params.TestChainConfig)
+	to := common.Address{0x22}
+
+	unsigned := []*Transaction{
+		NewTransaction(1, to, big.NewInt(1), 21000, big.NewInt(1), nil),
+		NewContractCreation(2, big.NewInt(2), 100000, big.NewInt(2), []byte{0x60, 0x00}),
+		NewTx(&DynamicFeeTx{ChainID: params.TestChainConfig.ChainID, Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(3), Gas: 30000, To: &to}),
+		NewTx(&DynamicFeeTx{ChainID: params.TestChainConfig.ChainID, Nonce: 4, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(3), Gas: 100000}),
+	}
+	txs := make(Transactions, len(unsigned))
+	for i, tx := range unsigned {
+		var err error
+		if txs[i], err = SignTx(tx, signer, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receipts := Receipts{
+		{Status: ReceiptStatusFailed, CumulativeGasUsed: 21000, Logs: []*Log{
+			{Address: common.BytesToAddress([]byte{0x11})},
+			{Address: common.BytesToAddress([]byte{0x01, 0x11}), Topics: []common.Hash{{1}}},
+		}},
+		{PostState: common.Hash{2}.Bytes(), CumulativeGasUsed: 74000},
+		{Status: ReceiptStatusSuccessful, CumulativeGasUsed: 100000, Logs: []*Log{
+			{Address: common.BytesToAddress([]byte{0x33}), Data: []byte{3}},
+		}},
+		{Status: ReceiptStatusSuccessful, CumulativeGasUsed: 160000},
+	}
+	for i := range receipts {
+		receipts[i].Bloom = BytesToBloom(LogsBloom(receipts[i].Logs).Bytes())
+	}
+	stored := make([]*ReceiptForStorage, len(receipts))
+	for i, r := range receipts {
+		stored[i] = (*ReceiptForStorage)(r)
+	}
+	data, err := rlp.EncodeToBytes(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*ReceiptForStorage
+	if err := rlp.DecodeBytes(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	derived := make(Receipts, len(decoded))
+	for i, r := range decoded {
+		derived[i] = (*Receipt)(r)
+	}
+
+	hash, number := common.BytesToHash([]byte{0x03, 0x14}), uint64(1)
+	if err := derived.DeriveFields(params.TestChainConfig, hash, number, txs); err != nil {
+		t.Fatalf("DeriveFields(...) = %v, want <nil>", err)
+	}
+	gasUsed := []uint64{21000, 53000, 26000, 60000}
+	logIndex := uint(0)
+	for i, r := range derived {
+		if r.Type != txs[i].Type() {
+			t.Errorf("receipts[%d].Type = %d, want %d", i, r.Type, txs[i].Type())
+		}
+		if r.TxHash != txs[i].Hash() {
+			t.Errorf("receipts[%d].TxHash = %s, want %s", i, r.TxHash.String(), txs[i].Hash().String())
+		}
+		if r.BlockHash != hash || r.BlockNumber.Uint64() != number || r.TransactionIndex != uint(i) {
+			t.Errorf("receipts[%d]: wrong position %x/%v/%d", i, r.BlockHash, r.BlockNumber, r.TransactionIndex)
+		}
+		if r.GasUsed != gasUsed[i] {
+			t.Errorf("receipts[%d].GasUsed = %d, want %d", i, r.GasUsed, gasUsed[i])
+		}
+		var contract common.Address
+		if txs[i].To() == nil {
+			contract = crypto.CreateAddress(from, txs[i].Nonce())
+		}
+		if r.ContractAddress != contract {
+			t.Errorf("receipts[%d].ContractAddress = %s, want %s", i, r.ContractAddress.String(), contract.String())
+		}
+		for j, log := range r.Logs {
+			if log.BlockNumber != number || log.BlockHash != hash || log.TxHash != txs[i].Hash() || log.TxIndex != uint(i) || log.Index != logIndex {
+				t.Errorf("receipts[%d].Logs[%d]: wrong derived fields %+v", i, j, log)
+			}
+			logIndex++
+		}
+		// The consensus encoding now matches the original receipt with its type.
+		receipts[i].Type = txs[i].Type()
+		if !bytes.Equal(derived.GetRlp(i), receipts.GetRlp(i)) {
+			t.Errorf("receipts[%d]: consensus encoding mismatch", i)
+		}
+	}
+
+	if err := derived.DeriveFields(params.TestChainConfig, hash, number, txs[:3]); err != errReceiptCount {
+		t.Errorf("got error %v for mismatching lengths, want errReceiptCount", err)
+	}
+	derived[2].CumulativeGasUsed = 1
+	if err := derived.DeriveFields(params.TestChainConfig, hash, number, txs); err == nil {
+		t.Error("decreasing cumulative gas used accepted")
+	}
+}
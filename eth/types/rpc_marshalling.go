@@ -0,0 +1,394 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/common/hexutil"
+)
+
+var (
+	errRPCBlockTxHashes = errors.New("block has transaction hashes only")
+	errRPCBlockUncles   = errors.New("block has uncle hashes only")
+)
+
+// RPCTransaction is the JSON-RPC representation of a transaction as returned
+// by eth_getTransactionByHash and in blocks with full transactions. The
+// inclusion fields are nil for pending transactions.
+type RPCTransaction struct {
+	Tx               *Transaction
+	From             common.Address
+	BlockHash        *common.Hash
+	BlockNumber      *big.Int
+	TransactionIndex *uint64
+	GasPrice         *big.Int // price paid per gas, the fee cap of pending dynamic fee transactions if nil
+}
+
+type rpcTransactionFields struct {
+	BlockHash        *common.Hash    `json:"blockHash"`
+	BlockNumber      *hexutil.Big    `json:"blockNumber"`
+	From             common.Address  `json:"from"`
+	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Hash             *common.Hash    `json:"hash,omitempty"`
+}
+
+// NewRPCTransaction returns the representation of the transaction at the
+// given index of a block with the given base fee, which determines the gas
+// price of dynamic fee transactions. The sender is recovered with the latest
+// signer for the transaction's chain ID.
+func NewRPCTransaction(tx *Transaction, blockHash common.Hash, blockNumber uint64, index uint64, baseFee *big.Int) (*RPCTransaction, error) {
+	from, err := Sender(LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCTransaction{
+		Tx:               tx,
+		From:             from,
+		BlockHash:        &blockHash,
+		BlockNumber:      new(big.Int).SetUint64(blockNumber),
+		TransactionIndex: &index,
+		GasPrice:         effectiveGasPrice(tx, baseFee),
+	}, nil
+}
+
+// MarshalJSON encodes the web3 RPC transaction format.
+func (t *RPCTransaction) MarshalJSON() ([]byte, error) {
+	price := t.GasPrice
+	if price == nil {
+		price = t.Tx.GasFeeCap()
+	}
+	fields, err := jsonFields(t.Tx, &rpcTransactionFields{
+		BlockHash:        t.BlockHash,
+		BlockNumber:      (*hexutil.Big)(t.BlockNumber),
+		From:             t.From,
+		TransactionIndex: (*hexutil.Uint64)(t.TransactionIndex),
+		GasPrice:         (*hexutil.Big)(price),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Fee fields which do not apply to the transaction type are left out.
+	for _, name := range []string{"maxFeePerGas", "maxPriorityFeePerGas"} {
+		if string(fields[name]) == "null" {
+			delete(fields, name)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the web3 RPC transaction format. It fails if the
+// encoded hash does not match the transaction.
+func (t *RPCTransaction) UnmarshalJSON(input []byte) error {
+	var tx Transaction
+	if err := tx.UnmarshalJSON(input); err != nil {
+		return err
+	}
+	var dec rpcTransactionFields
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Hash != nil && *dec.Hash != tx.Hash() {
+		return fmt.Errorf("transaction hash mismatch: have %x, computed %x", *dec.Hash, tx.Hash())
+	}
+	*t = RPCTransaction{
+		Tx:               &tx,
+		From:             dec.From,
+		BlockHash:        dec.BlockHash,
+		BlockNumber:      (*big.Int)(dec.BlockNumber),
+		TransactionIndex: (*uint64)(dec.TransactionIndex),
+		GasPrice:         (*big.Int)(dec.GasPrice),
+	}
+	return nil
+}
+
+// RPCBlock is the JSON-RPC representation of a block as returned by
+// eth_getBlockByHash and eth_getBlockByNumber. Depending on FullTx, the
+// block carries its transactions in Transactions or only their hashes in
+// TxHashes.
+type RPCBlock struct {
+	Header          *Header
+	Size            uint64
+	TotalDifficulty *big.Int // omitted if nil
+	FullTx          bool
+	TxHashes        []common.Hash
+	Transactions    []*RPCTransaction
+	Uncles          []common.Hash
+}
+
+type rpcBlockFields struct {
+	Size            hexutil.Uint64    `json:"size"`
+	TotalDifficulty *hexutil.Big      `json:"totalDifficulty,omitempty"`
+	Transactions    []json.RawMessage `json:"transactions"`
+	Uncles          []common.Hash     `json:"uncles"`
+	Hash            *common.Hash      `json:"hash,omitempty"`
+}
+
+// NewRPCBlock returns the representation of b, with full transactions if
+// fullTx is set. The gas price of dynamic fee transactions is derived from
+// the base fee of the block.
+func NewRPCBlock(b *Block, fullTx bool) (*RPCBlock, error) {
+	rb := &RPCBlock{
+		Header: b.Header(),
+		Size:   uint64(b.Size()),
+		FullTx: fullTx,
+		Uncles: make([]common.Hash, len(b.Uncles())),
+	}
+	for i, uncle := range b.Uncles() {
+		rb.Uncles[i] = uncle.Hash()
+	}
+	txs := b.Transactions()
+	if fullTx {
+		rb.Transactions = make([]*RPCTransaction, len(txs))
+		for i, tx := range txs {
+			rtx, err := NewRPCTransaction(tx, b.Hash(), b.NumberU64(), uint64(i), b.BaseFee())
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+			rb.Transactions[i] = rtx
+		}
+	} else {
+		rb.TxHashes = make([]common.Hash, len(txs))
+		for i, tx := range txs {
+			rb.TxHashes[i] = tx.Hash()
+		}
+	}
+	return rb, nil
+}
+
+// MarshalJSON encodes the web3 RPC block format.
+func (b *RPCBlock) MarshalJSON() ([]byte, error) {
+	enc := rpcBlockFields{
+		Size:            hexutil.Uint64(b.Size),
+		TotalDifficulty: (*hexutil.Big)(b.TotalDifficulty),
+		Transactions:    []json.RawMessage{},
+		Uncles:          b.Uncles,
+	}
+	if enc.Uncles == nil {
+		enc.Uncles = []common.Hash{}
+	}
+	var items []interface{}
+	if b.FullTx {
+		for _, tx := range b.Transactions {
+			items = append(items, tx)
+		}
+	} else {
+		for _, hash := range b.TxHashes {
+			items = append(items, hash)
+		}
+	}
+	for _, item := range items {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		enc.Transactions = append(enc.Transactions, raw)
+	}
+	fields, err := jsonFields(b.Header, &enc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the web3 RPC block format. The transactions may be
+// given as hashes or as full objects. It fails if the encoded hash does not
+// match the header.
+func (b *RPCBlock) UnmarshalJSON(input []byte) error {
+	var header Header
+	if err := header.UnmarshalJSON(input); err != nil {
+		return err
+	}
+	var dec rpcBlockFields
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Hash != nil && *dec.Hash != header.Hash() {
+		return fmt.Errorf("block hash mismatch: have %x, computed %x", *dec.Hash, header.Hash())
+	}
+	*b = RPCBlock{
+		Header:          &header,
+		Size:            uint64(dec.Size),
+		TotalDifficulty: (*big.Int)(dec.TotalDifficulty),
+		Uncles:          dec.Uncles,
+	}
+	for i, raw := range dec.Transactions {
+		if raw = bytes.TrimSpace(raw); len(raw) > 0 && raw[0] == '"' {
+			var hash common.Hash
+			if err := json.Unmarshal(raw, &hash); err != nil {
+				return fmt.Errorf("transaction %d: %v", i, err)
+			}
+			b.TxHashes = append(b.TxHashes, hash)
+			continue
+		}
+		var tx RPCTransaction
+		if err := tx.UnmarshalJSON(raw); err != nil {
+			return fmt.Errorf("transaction %d: %v", i, err)
+		}
+		b.Transactions = append(b.Transactions, &tx)
+	}
+	if len(b.TxHashes) > 0 && len(b.Transactions) > 0 {
+		return errors.New("block mixes transaction hashes and objects")
+	}
+	b.FullTx = len(b.Transactions) > 0
+	return nil
+}
+
+// Block assembles the block. This requires full transactions and no uncles,
+// whose headers are not part of the RPC representation. The transactions
+// are checked against the header's transaction root.
+func (b *RPCBlock) Block() (*Block, error) {
+	if len(b.TxHashes) > 0 {
+		return nil, errRPCBlockTxHashes
+	}
+	if len(b.Uncles) > 0 {
+		return nil, errRPCBlockUncles
+	}
+	txs := make(Transactions, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		txs[i] = tx.Tx
+	}
+	if root := DeriveSha(txs); root != b.Header.TxHash {
+		return nil, fmt.Errorf("transaction root mismatch: have %x, computed %x", b.Header.TxHash, root)
+	}
+	return NewBlockWithHeader(b.Header).WithBody(txs, nil), nil
+}
+
+// RPCReceipt is the JSON-RPC representation of a receipt as returned by
+// eth_getTransactionReceipt. Besides the receipt, it carries fields derived
+// from the transaction.
+type RPCReceipt struct {
+	Receipt           *Receipt
+	From              common.Address
+	To                *common.Address
+	EffectiveGasPrice *big.Int // omitted if nil
+}
+
+type rpcReceiptFields struct {
+	Type              hexutil.Uint64  `json:"type"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice,omitempty"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*Log          `json:"logs"`
+}
+
+// NewRPCReceipt returns the representation of the receipt of tx. The base
+// fee of the block is needed to derive the effective gas price of dynamic
+// fee transactions; if it is nil, the price is left out for them.
+func NewRPCReceipt(receipt *Receipt, tx *Transaction, baseFee *big.Int) (*RPCReceipt, error) {
+	from, err := Sender(LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCReceipt{
+		Receipt:           receipt,
+		From:              from,
+		To:                tx.To(),
+		EffectiveGasPrice: effectiveGasPrice(tx, baseFee),
+	}, nil
+}
+
+// effectiveGasPrice returns the price per gas paid by tx in a block with the
+// given base fee, which is min(tip + base fee, fee cap) for dynamic fee
+// transactions. It returns nil for those if the base fee is unknown.
+func effectiveGasPrice(tx *Transaction, baseFee *big.Int) *big.Int {
+	if tx.Type() != DynamicFeeTxType {
+		return tx.GasPrice()
+	}
+	if baseFee == nil {
+		return nil
+	}
+	price := new(big.Int).Add(tx.GasTipCap(), baseFee)
+	if price.Cmp(tx.GasFeeCap()) > 0 {
+		price = tx.GasFeeCap()
+	}
+	return price
+}
+
+// MarshalJSON encodes the web3 RPC receipt format. Like the RPC API, it
+// includes the post state root of pre-Byzantium receipts and the status
+// otherwise, and encodes a missing contract address as null. Fields of
+// Receipt which are not part of the RPC format are left out.
+func (r *RPCReceipt) MarshalJSON() ([]byte, error) {
+	enc := rpcReceiptFields{
+		Type:              hexutil.Uint64(r.Receipt.Type),
+		From:              r.From,
+		To:                r.To,
+		EffectiveGasPrice: (*hexutil.Big)(r.EffectiveGasPrice),
+		Logs:              r.Receipt.Logs,
+	}
+	if enc.Logs == nil {
+		enc.Logs = []*Log{}
+	}
+	if r.Receipt.ContractAddress != (common.Address{}) {
+		enc.ContractAddress = &r.Receipt.ContractAddress
+	}
+	fields, err := jsonFields(r.Receipt, &enc)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Receipt.PostState) > 0 {
+		delete(fields, "status")
+	} else {
+		delete(fields, "root")
+	}
+	delete(fields, "spawned transactionHash")
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the web3 RPC receipt format.
+func (r *RPCReceipt) UnmarshalJSON(input []byte) error {
+	var receipt Receipt
+	if err := receipt.UnmarshalJSON(input); err != nil {
+		return err
+	}
+	var dec rpcReceiptFields
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*r = RPCReceipt{
+		Receipt:           &receipt,
+		From:              dec.From,
+		To:                dec.To,
+		EffectiveGasPrice: (*big.Int)(dec.EffectiveGasPrice),
+	}
+	return nil
+}
+
+// jsonFields encodes the given values, which must encode as JSON objects,
+// and collects their fields. Later fields override earlier ones of the same
+// name.
+func jsonFields(values ...interface{}) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	for _, v := range values {
+		enc, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(enc, &fields); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
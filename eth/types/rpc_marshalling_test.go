@@ -0,0 +1,432 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+)
+
+// Mainnet fixtures as returned by eth_getBlockByNumber and
+// eth_getTransactionByHash.
+var (
+	mainnetGenesisJSON = `{
+  "difficulty": "0x400000000",
+  "extraData": "0x11bbe8db4e347b4e8c937c1c8370e4b5ed33adb3db69cbdb7a38e1e50b1b82fa",
+  "gasLimit": "0x1388",
+  "gasUsed": "0x0",
+  "hash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+  "logsBloom": "0x` + zeroBloomHex + `",
+  "miner": "0x0000000000000000000000000000000000000000",
+  "mixHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+  "nonce": "0x0000000000000042",
+  "number": "0x0",
+  "parentHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+  "receiptsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+  "sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+  "stateRoot": "0xd7f8974fb5ac78d9ac099b9ad5018bedc2ce0a72dad1827a1709da30580f0544",
+  "timestamp": "0x0",
+  "transactionsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+}`
+
+	mainnetBlock1JSON = `{
+  "difficulty": "0x3ff800000",
+  "extraData": "0x476574682f76312e302e302f6c696e75782f676f312e342e32",
+  "gasLimit": "0x1388",
+  "gasUsed": "0x0",
+  "hash": "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+  "logsBloom": "0x` + zeroBloomHex + `",
+  "miner": "0x05a56e2d52c817161883f50c441c3228cfe54d9f",
+  "mixHash": "0x969b900de27b6ac6a67742365dd65f55a0526c41fd18e1b16f1a1215c2e66f59",
+  "nonce": "0x539bd4979fef1ec4",
+  "number": "0x1",
+  "parentHash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+  "receiptsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+  "sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+  "size": "0x219",
+  "stateRoot": "0xd67e4d450343046425ae4271474353857ab860dbc0a1dde64b41b5cd3a532bf3",
+  "timestamp": "0x55ba4224",
+  "totalDifficulty": "0x7ff800000",
+  "transactions": [],
+  "transactionsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+  "uncles": []
+}`
+
+	// The first transaction on mainnet, in block 46147.
+	mainnetTxJSON = `{
+  "blockHash": "0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd",
+  "blockNumber": "0xb443",
+  "from": "0xa1e4380a3b1f749673e270229993ee55f35663b4",
+  "gas": "0x5208",
+  "gasPrice": "0x2d79883d2000",
+  "hash": "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060",
+  "input": "0x",
+  "nonce": "0x0",
+  "r": "0x88ff6cf0fefd94db46111149ae4bfc179e9b94721fffd821d38d16464b3f71d0",
+  "s": "0x45e0aff800961cfce805daef7016b9b675c137a6a41a548f7b60a3484c06a33a",
+  "to": "0x5df9b87991262f6ba471f09758cde1c0fc1de734",
+  "transactionIndex": "0x0",
+  "type": "0x0",
+  "v": "0x1c",
+  "value": "0x7a69"
+}`
+
+	// The receipt of the first mainnet transaction, as returned by
+	// eth_getTransactionReceipt. Its post state root predates Byzantium.
+	mainnetReceiptJSON = `{
+  "blockHash": "0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd",
+  "blockNumber": "0xb443",
+  "contractAddress": null,
+  "cumulativeGasUsed": "0x5208",
+  "effectiveGasPrice": "0x2d79883d2000",
+  "from": "0xa1e4380a3b1f749673e270229993ee55f35663b4",
+  "gasUsed": "0x5208",
+  "logs": [],
+  "logsBloom": "0x` + zeroBloomHex + `",
+  "root": "0x96a8e009d2b88b1483e6941e6812e32263b05683fac202abc622a3e31aed1957",
+  "to": "0x5df9b87991262f6ba471f09758cde1c0fc1de734",
+  "transactionHash": "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060",
+  "transactionIndex": "0x0",
+  "type": "0x0"
+}`
+)
+
+var zeroBloomHex = strings.Repeat("00", BloomByteLength)
+
+// assertJSONEqual compares JSON documents ignoring formatting and field order.
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("JSON mismatch:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestHeaderJSON(t *testing.T) {
+	var header Header
+	if err := json.Unmarshal([]byte(mainnetGenesisJSON), &header); err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"); header.Hash() != want {
+		t.Fatalf("wrong hash %x", header.Hash())
+	}
+	enc, err := json.Marshal(&header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, enc, mainnetGenesisJSON)
+
+	if err := json.Unmarshal([]byte(`{"parentHash":"0x0000000000000000000000000000000000000000000000000000000000000000"}`), &header); err == nil {
+		t.Fatal("header with missing fields accepted")
+	}
+}
+
+func TestRPCBlockHashes(t *testing.T) {
+	var rb RPCBlock
+	if err := json.Unmarshal([]byte(mainnetBlock1JSON), &rb); err != nil {
+		t.Fatal(err)
+	}
+	if rb.FullTx || rb.TotalDifficulty.Uint64() != 0x7ff800000 {
+		t.Fatalf("unexpected block fields: %+v", rb)
+	}
+	enc, err := json.Marshal(&rb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, enc, mainnetBlock1JSON)
+
+	block, err := rb.Block()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Hash() != rb.Header.Hash() || uint64(block.Size()) != rb.Size {
+		t.Fatalf("assembled block: hash %x, size %d", block.Hash(), uint64(block.Size()))
+	}
+	fresh, err := NewRPCBlock(block, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh.TotalDifficulty = rb.TotalDifficulty
+	if enc, err = json.Marshal(fresh); err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, enc, mainnetBlock1JSON)
+
+	tampered := strings.Replace(mainnetBlock1JSON, `"nonce": "0x539bd4979fef1ec4"`, `"nonce": "0x539bd4979fef1ec5"`, 1)
+	if err := json.Unmarshal([]byte(tampered), &rb); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("tampered block: got error %v", err)
+	}
+}
+
+func TestRPCTransaction(t *testing.T) {
+	var rtx RPCTransaction
+	if err := json.Unmarshal([]byte(mainnetTxJSON), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	from, err := Sender(HomesteadSigner{}, rtx.Tx)
+	if err != nil || from != rtx.From {
+		t.Fatalf("recovered sender %x (%v), want %x", from, err, rtx.From)
+	}
+	enc, err := json.Marshal(&rtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, enc, mainnetTxJSON)
+
+	fresh, err := NewRPCTransaction(rtx.Tx, *rtx.BlockHash, 46147, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc, err = json.Marshal(fresh); err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, enc, mainnetTxJSON)
+
+	tampered := strings.Replace(mainnetTxJSON, `"value": "0x7a69"`, `"value": "0x7a6a"`, 1)
+	if err := json.Unmarshal([]byte(tampered), &rtx); err == nil {
+		t.Fatal("tampered transaction accepted")
+	}
+
+	// Senders which cannot be recovered are reported.
+	unsigned := NewTransaction(0, testAddr, big.NewInt(1), 21000, big.NewInt(1), nil)
+	if _, err := NewRPCTransaction(unsigned, common.Hash{}, 1, 0, nil); err == nil {
+		t.Fatal("unsigned transaction accepted")
+	}
+}
+
+func TestRPCTransactionGasPrice(t *testing.T) {
+	_, _, txs := testSignedTxs(t)
+	dynamic := txs[3] // tip 1, fee cap 5
+	for _, test := range []struct {
+		baseFee *big.Int
+		want    string
+	}{
+		{nil, `"0x5"`},             // pending: fee cap
+		{big.NewInt(2), `"0x3"`},   // tip + base fee
+		{big.NewInt(4), `"0x5"`},   // capped by the fee cap
+		{big.NewInt(100), `"0x5"`}, // capped by the fee cap
+	} {
+		rtx, err := NewRPCTransaction(dynamic, common.Hash{1}, 1, 0, test.baseFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc, err := json.Marshal(rtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields map[string]json.RawMessage
+		json.Unmarshal(enc, &fields)
+		if string(fields["gasPrice"]) != test.want {
+			t.Errorf("base fee %v: got gas price %s, want %s", test.baseFee, fields["gasPrice"], test.want)
+		}
+		// The gas price survives a round trip.
+		var dec RPCTransaction
+		if err := json.Unmarshal(enc, &dec); err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := json.Marshal(&dec); string(again) != string(enc) {
+			t.Errorf("base fee %v: round trip changed the encoding\n%s\n%s", test.baseFee, enc, again)
+		}
+	}
+	// Legacy transactions pay their gas price regardless of the base fee.
+	rtx, err := NewRPCTransaction(txs[0], common.Hash{1}, 1, 0, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rtx.GasPrice.Cmp(txs[0].GasPrice()) != 0 {
+		t.Errorf("legacy gas price %v, want %v", rtx.GasPrice, txs[0].GasPrice())
+	}
+}
+
+func TestRPCBlockFullTx(t *testing.T) {
+	var rtx RPCTransaction
+	if err := json.Unmarshal([]byte(mainnetTxJSON), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	_, _, signed := testSignedTxs(t)
+	txs := append(Transactions{rtx.Tx}, signed[:2]...)
+	header := &Header{
+		Number:     big.NewInt(46147),
+		Difficulty: big.NewInt(1),
+		Time:       big.NewInt(1),
+		TxHash:     DeriveSha(txs),
+		BaseFee:    big.NewInt(1),
+	}
+	block := NewBlockWithHeader(header).WithBody(txs, nil)
+
+	rpcBlock, err := NewRPCBlock(block, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := json.Marshal(rpcBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rb RPCBlock
+	if err := json.Unmarshal(enc, &rb); err != nil {
+		t.Fatal(err)
+	}
+	if !rb.FullTx || len(rb.Transactions) != len(txs) {
+		t.Fatalf("decoded %d transactions, full %v", len(rb.Transactions), rb.FullTx)
+	}
+	for i, tx := range rb.Transactions {
+		if tx.Tx.Hash() != txs[i].Hash() || *tx.BlockHash != block.Hash() || *tx.TransactionIndex != uint64(i) || tx.BlockNumber.Uint64() != 46147 {
+			t.Errorf("transaction %d: wrong inclusion fields", i)
+		}
+	}
+	if rb.Header.BaseFee.Int64() != 1 {
+		t.Errorf("wrong base fee %v", rb.Header.BaseFee)
+	}
+	if rb.Transactions[0].From != common.HexToAddress("0xa1e4380a3b1f749673e270229993ee55f35663b4") {
+		t.Errorf("wrong sender %x", rb.Transactions[0].From)
+	}
+	decoded, err := rb.Block()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash() != block.Hash() || len(decoded.Transactions()) != len(txs) {
+		t.Fatal("assembled block differs")
+	}
+
+	// Hash-only blocks cannot be assembled.
+	rpcBlock, _ = NewRPCBlock(block, false)
+	enc, _ = json.Marshal(rpcBlock)
+	if err := json.Unmarshal(enc, &rb); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rb.Block(); err != errRPCBlockTxHashes {
+		t.Fatalf("got error %v, want %v", err, errRPCBlockTxHashes)
+	}
+}
+
+func TestRPCReceipt(t *testing.T) {
+	var rtx RPCTransaction
+	if err := json.Unmarshal([]byte(mainnetTxJSON), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	// The decoded receipt re-encodes to the recorded document.
+	var decoded RPCReceipt
+	if err := json.Unmarshal([]byte(mainnetReceiptJSON), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	receipt := decoded.Receipt
+	if receipt.TxHash != rtx.Tx.Hash() || receipt.CumulativeGasUsed != 21000 || receipt.BlockNumber.Uint64() != 46147 {
+		t.Fatalf("unexpected receipt fields: %+v", receipt)
+	}
+	rr, err := NewRPCReceipt(receipt, rtx.Tx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := json.Marshal(rr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, enc, mainnetReceiptJSON)
+
+	if err := json.Unmarshal(enc, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Receipt.PostState, receipt.PostState) || decoded.From != rtx.From || *decoded.To != *rtx.Tx.To() {
+		t.Fatalf("decoded receipt differs: %+v", decoded)
+	}
+
+	// Receipts with status and logs of a contract creation.
+	log := &Log{
+		Address:     common.HexToAddress("0x01"),
+		Topics:      []common.Hash{{1}},
+		Data:        []byte{1, 2},
+		BlockNumber: 7,
+		TxHash:      common.Hash{2},
+		TxIndex:     1,
+		BlockHash:   common.Hash{3},
+		Index:       4,
+	}
+	receipt = &Receipt{
+		Status:          ReceiptStatusSuccessful,
+		Logs:            []*Log{log},
+		ContractAddress: common.HexToAddress("0x02"),
+	}
+	_, _, signed := testSignedTxs(t)
+	creation := signed[1]
+	if rr, err = NewRPCReceipt(receipt, creation, nil); err != nil {
+		t.Fatal(err)
+	}
+	enc, err = json.Marshal(rr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	json.Unmarshal(enc, &fields)
+	for _, name := range []string{"root", "spawned transactionHash"} {
+		if _, ok := fields[name]; ok {
+			t.Errorf("%s encoded for status receipt", name)
+		}
+	}
+	for name, want := range map[string]string{
+		"status":          `"0x1"`,
+		"to":              `null`,
+		"contractAddress": `"0x0000000000000000000000000000000000000002"`,
+		"logs":            `[{"address":"0x0000000000000000000000000000000000000001","topics":["0x0100000000000000000000000000000000000000000000000000000000000000"],"data":"0x0102","blockNumber":"0x7","transactionHash":"0x0200000000000000000000000000000000000000000000000000000000000000","transactionIndex":"0x1","blockHash":"0x0300000000000000000000000000000000000000000000000000000000000000","logIndex":"0x4","removed":false}]`,
+	} {
+		if string(fields[name]) != want {
+			t.Errorf("%s: got %s, want %s", name, fields[name], want)
+		}
+	}
+	if err := json.Unmarshal(enc, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Receipt.Logs, receipt.Logs) {
+		t.Error("logs differ after round trip")
+	}
+}
+
+func TestRPCReceiptEffectiveGasPrice(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	tx, err := SignNewTx(key, NewLondonSigner(big.NewInt(1)), &DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(10), Gas: 21000, Value: big.NewInt(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		baseFee *big.Int
+		want    *big.Int
+	}{
+		{nil, nil},
+		{big.NewInt(5), big.NewInt(7)},
+		{big.NewInt(9), big.NewInt(10)},
+	} {
+		r, err := NewRPCReceipt(&Receipt{}, tx, test.baseFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := r.EffectiveGasPrice
+		if (got == nil) != (test.want == nil) || (got != nil && got.Cmp(test.want) != 0) {
+			t.Errorf("base fee %v: got %v, want %v", test.baseFee, got, test.want)
+		}
+	}
+}
@@ -17,49 +17,73 @@
 package types
 
 import (
+	"bytes"
 	"container/heap"
 	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"sync/atomic"
 
 	"github.com/arcology-network/3rd-party/eth/common"
 	"github.com/arcology-network/3rd-party/eth/common/hexutil"
+	"github.com/arcology-network/3rd-party/eth/common/math"
 	"github.com/arcology-network/3rd-party/eth/crypto"
 	"github.com/arcology-network/3rd-party/eth/rlp"
 )
 
-//go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
-
 var (
-	ErrInvalidSig = errors.New("invalid transaction v, r, s values")
+	ErrInvalidSig           = errors.New("invalid transaction v, r, s values")
+	ErrUnexpectedProtection = errors.New("transaction type does not supported EIP-155 protected signatures")
+	ErrInvalidTxType        = errors.New("transaction type not valid in this context")
+	ErrTxTypeNotSupported   = errors.New("transaction type not supported")
+	errEmptyTypedTx         = errors.New("empty typed transaction bytes")
+)
+
+// Transaction types.
+const (
+	LegacyTxType = iota
+	AccessListTxType
+	DynamicFeeTxType
 )
 
+// Transaction is an Ethereum transaction.
 type Transaction struct {
-	data txdata
+	inner TxData // Consensus contents of a transaction
+
 	// caches
 	hash atomic.Value
 	size atomic.Value
 	from atomic.Value
 }
 
-type txdata struct {
-	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
-	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
-	GasLimit     uint64          `json:"gas"      gencodec:"required"`
-	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
-	Amount       *big.Int        `json:"value"    gencodec:"required"`
-	Payload      []byte          `json:"input"    gencodec:"required"`
-
-	// Signature values
-	V *big.Int `json:"v" gencodec:"required"`
-	R *big.Int `json:"r" gencodec:"required"`
-	S *big.Int `json:"s" gencodec:"required"`
-
-	// This is only used when marshaling to JSON.
-	Hash *common.Hash `json:"hash" rlp:"-"`
+// NewTx creates a new transaction.
+func NewTx(inner TxData) *Transaction {
+	tx := new(Transaction)
+	tx.setDecoded(inner.copy(), 0)
+	return tx
+}
 
-	//Addrfrom *common.Address `json:"from"       rlp:"nil"` // test use
+// TxData is the underlying data of a transaction.
+//
+// This is implemented by LegacyTx, AccessListTx and DynamicFeeTx.
+type TxData interface {
+	txType() byte // returns the type ID
+	copy() TxData // creates a deep copy and initializes all fields
+
+	chainID() *big.Int
+	accessList() AccessList
+	data() []byte
+	gas() uint64
+	gasPrice() *big.Int
+	gasTipCap() *big.Int
+	gasFeeCap() *big.Int
+	value() *big.Int
+	nonce() uint64
+	to() *common.Address
+
+	rawSignatureValues() (v, r, s *big.Int)
+	setSignatureValues(chainID, v, r, s *big.Int)
 }
 
 func NewTransactionTS(istx int, nonce uint64, from, to common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
@@ -71,149 +95,223 @@ func NewTransactionTS(istx int, nonce uint64, from, to common.Address, amount *b
 }
 
 func newTransactionTS(nonce uint64, from, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
-	if len(data) > 0 {
-		data = common.CopyBytes(data)
-	}
-	d := txdata{
-		AccountNonce: nonce,
-		//Addrfrom:     from,
-		Recipient: to,
-		Payload:   data,
-		Amount:    new(big.Int),
-		GasLimit:  gasLimit,
-		Price:     new(big.Int),
-		V:         new(big.Int),
-		R:         new(big.Int),
-		S:         new(big.Int),
-	}
-	if amount != nil {
-		d.Amount.Set(amount)
+	return newTransaction(nonce, to, amount, gasLimit, gasPrice, data)
+}
+
+// EncodeRLP implements rlp.Encoder
+func (tx *Transaction) EncodeRLP(w io.Writer) error {
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, tx.inner)
 	}
-	if gasPrice != nil {
-		d.Price.Set(gasPrice)
+	// It's an EIP-2718 typed TX envelope.
+	buf := new(bytes.Buffer)
+	if err := tx.encodeTyped(buf); err != nil {
+		return err
 	}
-
-	return &Transaction{data: d}
+	return rlp.Encode(w, buf.Bytes())
 }
 
-type txdataMarshaling struct {
-	AccountNonce hexutil.Uint64
-	Price        *hexutil.Big
-	GasLimit     hexutil.Uint64
-	Amount       *hexutil.Big
-	Payload      hexutil.Bytes
-	V            *hexutil.Big
-	R            *hexutil.Big
-	S            *hexutil.Big
+// encodeTyped writes the canonical encoding of a typed transaction to w.
+func (tx *Transaction) encodeTyped(w *bytes.Buffer) error {
+	w.WriteByte(tx.Type())
+	return rlp.Encode(w, tx.inner)
 }
 
-func NewTransaction(nonce uint64, to common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
-	return newTransaction(nonce, &to, amount, gasLimit, gasPrice, data)
+// MarshalBinary returns the canonical encoding of the transaction.
+// For legacy transactions, it returns the RLP encoding. For EIP-2718 typed
+// transactions, it returns the type and payload.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.Type() == LegacyTxType {
+		return rlp.EncodeToBytes(tx.inner)
+	}
+	var buf bytes.Buffer
+	err := tx.encodeTyped(&buf)
+	return buf.Bytes(), err
 }
 
-func NewContractCreation(nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
-	return newTransaction(nonce, nil, amount, gasLimit, gasPrice, data)
+// DecodeRLP implements rlp.Decoder
+func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
+	kind, size, err := s.Kind()
+	switch {
+	case err != nil:
+		return err
+	case kind == rlp.List:
+		// It's a legacy transaction.
+		var inner LegacyTx
+		err := s.Decode(&inner)
+		if err == nil {
+			tx.setDecoded(&inner, int(rlp.ListSize(size)))
+		}
+		return err
+	default:
+		// It's an EIP-2718 typed TX envelope.
+		var b []byte
+		if b, err = s.Bytes(); err != nil {
+			return err
+		}
+		inner, err := tx.decodeTyped(b, s.Strict())
+		if err == nil {
+			tx.setDecoded(inner, len(b))
+		}
+		var cerr *rlp.CanonError
+		if errors.As(err, &cerr) {
+			// Make the offset relative to the input of s.
+			cerr.Offset += int(s.Offset()) - len(b) + 1
+		}
+		return err
+	}
 }
 
-func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
-	if len(data) > 0 {
-		data = common.CopyBytes(data)
+// UnmarshalBinary decodes the canonical encoding of transactions.
+// It supports legacy RLP transactions and EIP-2718 typed transactions.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) > 0 && b[0] > 0x7f {
+		// It's a legacy transaction.
+		var data LegacyTx
+		err := rlp.DecodeBytes(b, &data)
+		if err != nil {
+			return err
+		}
+		tx.setDecoded(&data, len(b))
+		return nil
 	}
-	d := txdata{
-		AccountNonce: nonce,
-		Recipient:    to,
-		Payload:      data,
-		Amount:       new(big.Int),
-		GasLimit:     gasLimit,
-		Price:        new(big.Int),
-		V:            new(big.Int),
-		R:            new(big.Int),
-		S:            new(big.Int),
+	// It's an EIP-2718 typed transaction envelope.
+	inner, err := tx.decodeTyped(b, false)
+	if err != nil {
+		return err
 	}
-	if amount != nil {
-		d.Amount.Set(amount)
+	tx.setDecoded(inner, len(b))
+	return nil
+}
+
+// decodeTyped decodes a typed transaction from the canonical format. In
+// strict mode, the payload is decoded with rlp.DecodeBytesStrict.
+func (tx *Transaction) decodeTyped(b []byte, strict bool) (TxData, error) {
+	if len(b) == 0 {
+		return nil, errEmptyTypedTx
 	}
-	if gasPrice != nil {
-		d.Price.Set(gasPrice)
+	decode := rlp.DecodeBytes
+	if strict {
+		decode = rlp.DecodeBytesStrict
+	}
+	switch b[0] {
+	case AccessListTxType:
+		var inner AccessListTx
+		err := decode(b[1:], &inner)
+		return &inner, err
+	case DynamicFeeTxType:
+		var inner DynamicFeeTx
+		err := decode(b[1:], &inner)
+		return &inner, err
+	default:
+		return nil, ErrTxTypeNotSupported
 	}
-
-	return &Transaction{data: d}
 }
 
-// ChainId returns which chain id this transaction was signed for (if at all)
-func (tx *Transaction) ChainId() *big.Int {
-	return deriveChainId(tx.data.V)
+// setDecoded sets the inner transaction and size after decoding.
+func (tx *Transaction) setDecoded(inner TxData, size int) {
+	tx.inner = inner
+	if size > 0 {
+		tx.size.Store(common.StorageSize(size))
+	}
 }
 
-// Protected returns whether the transaction is protected from replay protection.
-func (tx *Transaction) Protected() bool {
-	return isProtectedV(tx.data.V)
+func sanityCheckSignature(v *big.Int, r *big.Int, s *big.Int, maybeProtected bool) error {
+	if isProtectedV(v) && !maybeProtected {
+		return ErrUnexpectedProtection
+	}
+
+	var plainV byte
+	if isProtectedV(v) {
+		chainID := deriveChainId(v).Uint64()
+		plainV = byte(v.Uint64() - 35 - 2*chainID)
+	} else if maybeProtected {
+		// Only EIP-155 signatures can be optionally protected. Since
+		// we determined this v value is not protected, it must be a
+		// raw 27 or 28.
+		plainV = byte(v.Uint64() - 27)
+	} else {
+		// If the signature is not optionally protected, we assume it
+		// must already be equal to the recovery id.
+		plainV = byte(v.Uint64())
+	}
+	if !crypto.ValidateSignatureValues(plainV, r, s, false) {
+		return ErrInvalidSig
+	}
+
+	return nil
 }
 
 func isProtectedV(V *big.Int) bool {
 	if V.BitLen() <= 8 {
 		v := V.Uint64()
-		return v != 27 && v != 28
+		return v != 27 && v != 28 && v != 1 && v != 0
 	}
 	// anything not 27 or 28 is considered protected
 	return true
 }
 
-// EncodeRLP implements rlp.Encoder
-func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
-}
-
-// DecodeRLP implements rlp.Decoder
-func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
-	if err == nil {
-		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+// Protected says whether the transaction is replay-protected.
+func (tx *Transaction) Protected() bool {
+	switch tx := tx.inner.(type) {
+	case *LegacyTx:
+		return tx.V != nil && isProtectedV(tx.V)
+	default:
+		return true
 	}
+}
 
-	return err
+// Type returns the transaction type.
+func (tx *Transaction) Type() uint8 {
+	return tx.inner.txType()
 }
 
-// MarshalJSON encodes the web3 RPC transaction format.
-func (tx *Transaction) MarshalJSON() ([]byte, error) {
-	hash := tx.Hash()
-	data := tx.data
-	data.Hash = &hash
-	return data.MarshalJSON()
+// ChainId returns the EIP155 chain ID of the transaction. The return value will always be
+// non-nil. For legacy transactions which are not replay-protected, the return value is
+// zero.
+func (tx *Transaction) ChainId() *big.Int {
+	return tx.inner.chainID()
 }
 
-// UnmarshalJSON decodes the web3 RPC transaction format.
-func (tx *Transaction) UnmarshalJSON(input []byte) error {
-	var dec txdata
-	if err := dec.UnmarshalJSON(input); err != nil {
-		return err
-	}
+// Data returns the input data of the transaction.
+func (tx *Transaction) Data() []byte { return common.CopyBytes(tx.inner.data()) }
 
-	withSignature := dec.V.Sign() != 0 || dec.R.Sign() != 0 || dec.S.Sign() != 0
-	if withSignature {
-		var V byte
-		if isProtectedV(dec.V) {
-			chainID := deriveChainId(dec.V).Uint64()
-			V = byte(dec.V.Uint64() - 35 - 2*chainID)
-		} else {
-			V = byte(dec.V.Uint64() - 27)
-		}
-		if !crypto.ValidateSignatureValues(V, dec.R, dec.S, false) {
-			return ErrInvalidSig
-		}
-	}
+// AccessList returns the access list of the transaction.
+func (tx *Transaction) AccessList() AccessList { return tx.inner.accessList() }
 
-	*tx = Transaction{data: dec}
-	return nil
+// Gas returns the gas limit of the transaction.
+func (tx *Transaction) Gas() uint64 { return tx.inner.gas() }
+
+// GasPrice returns the gas price of the transaction.
+func (tx *Transaction) GasPrice() *big.Int { return new(big.Int).Set(tx.inner.gasPrice()) }
+
+// GasTipCap returns the gasTipCap per gas of the transaction.
+func (tx *Transaction) GasTipCap() *big.Int { return new(big.Int).Set(tx.inner.gasTipCap()) }
+
+// GasFeeCap returns the fee cap per gas of the transaction.
+func (tx *Transaction) GasFeeCap() *big.Int { return new(big.Int).Set(tx.inner.gasFeeCap()) }
+
+// EffectiveGasTip returns the tip per gas the miner receives given the base
+// fee of the block. The result is negative if the fee cap is below the base
+// fee. A nil base fee leaves the tip cap.
+func (tx *Transaction) EffectiveGasTip(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return tx.GasTipCap()
+	}
+	tip := new(big.Int).Sub(tx.inner.gasFeeCap(), baseFee)
+	if tip.Cmp(tx.inner.gasTipCap()) > 0 {
+		tip.Set(tx.inner.gasTipCap())
+	}
+	return tip
 }
 
-func (tx *Transaction) Data() []byte       { return common.CopyBytes(tx.data.Payload) }
-func (tx *Transaction) Gas() uint64        { return tx.data.GasLimit }
-func (tx *Transaction) GasPrice() *big.Int { return new(big.Int).Set(tx.data.Price) }
-func (tx *Transaction) Value() *big.Int    { return new(big.Int).Set(tx.data.Amount) }
-func (tx *Transaction) Nonce() uint64      { return tx.data.AccountNonce }
-func (tx *Transaction) CheckNonce() bool   { return true }
+// Value returns the ether amount of the transaction.
+func (tx *Transaction) Value() *big.Int { return new(big.Int).Set(tx.inner.value()) }
+
+// Nonce returns the sender account nonce of the transaction.
+func (tx *Transaction) Nonce() uint64 { return tx.inner.nonce() }
+
+func (tx *Transaction) CheckNonce() bool { return true }
 
 /***********************************************************************/
 func (tx *Transaction) GobEncode() ([]byte, error) {
@@ -224,20 +322,11 @@ func (tx *Transaction) GobDecode(data []byte) error {
 }
 
 /**************************************************************************/
-// func (tx *Transaction) From() *common.Address {
-
-// 	from := *tx.data.Addrfrom
-// 	return &from
-// }
 
 // To returns the recipient address of the transaction.
 // It returns nil if the transaction is a contract creation.
 func (tx *Transaction) To() *common.Address {
-	if tx.data.Recipient == nil {
-		return nil
-	}
-	to := *tx.data.Recipient
-	return &to
+	return copyAddressPtr(tx.inner.to())
 }
 
 // Hash hashes the RLP encoding of tx.
@@ -246,9 +335,14 @@ func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
-	tx.hash.Store(v)
-	return v
+	var h common.Hash
+	if tx.Type() == LegacyTxType {
+		h = rlpHash(tx.inner)
+	} else {
+		h = prefixedRlpHash(tx.Type(), tx.inner)
+	}
+	tx.hash.Store(h)
+	return h
 }
 
 // Size returns the true RLP encoded storage size of the transaction, either by
@@ -258,26 +352,37 @@ func (tx *Transaction) Size() common.StorageSize {
 		return size.(common.StorageSize)
 	}
 	c := writeCounter(0)
-	rlp.Encode(&c, &tx.data)
+	rlp.Encode(&c, &tx.inner)
+	if tx.Type() != LegacyTxType {
+		c += 1 // type byte
+	}
 	tx.size.Store(common.StorageSize(c))
 	return common.StorageSize(c)
 }
 
 // AsMessage returns the transaction as a core.Message.
 //
-// AsMessage requires a signer to derive the sender.
+// AsMessage requires a signer to derive the sender. If baseFee is given, the
+// gas price of the message is the effective gas price the transaction pays
+// in a block with that base fee, min(gasTipCap+baseFee, gasFeeCap).
 //
 // XXX Rename message to something less arbitrary?
-func (tx *Transaction) AsMessage(s Signer) (Message, error) {
+func (tx *Transaction) AsMessage(s Signer, baseFee *big.Int) (Message, error) {
 	msg := Message{
-		nonce:      tx.data.AccountNonce,
-		gasLimit:   tx.data.GasLimit,
-		gasPrice:   new(big.Int).Set(tx.data.Price),
-		to:         tx.data.Recipient,
-		amount:     tx.data.Amount,
-		data:       tx.data.Payload,
+		nonce:      tx.Nonce(),
+		gasLimit:   tx.Gas(),
+		gasPrice:   tx.GasPrice(),
+		gasFeeCap:  tx.GasFeeCap(),
+		gasTipCap:  tx.GasTipCap(),
+		to:         tx.To(),
+		amount:     tx.inner.value(),
+		data:       tx.inner.data(),
+		accessList: tx.AccessList(),
 		checkNonce: true,
 	}
+	if baseFee != nil {
+		msg.gasPrice = math.BigMin(msg.gasPrice.Add(msg.gasTipCap, baseFee), msg.gasFeeCap)
+	}
 
 	var err error
 	msg.from, err = Sender(s, tx)
@@ -285,26 +390,28 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 }
 
 // WithSignature returns a new transaction with the given signature.
-// This signature needs to be formatted as described in the yellow paper (v+27).
+// This signature needs to be in the [R || S || V] format where V is 0 or 1.
 func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, error) {
 	r, s, v, err := signer.SignatureValues(tx, sig)
 	if err != nil {
 		return nil, err
 	}
-	cpy := &Transaction{data: tx.data}
-	cpy.data.R, cpy.data.S, cpy.data.V = r, s, v
-	return cpy, nil
+	cpy := tx.inner.copy()
+	cpy.setSignatureValues(signer.ChainID(), v, r, s)
+	return &Transaction{inner: cpy}, nil
 }
 
-// Cost returns amount + gasprice * gaslimit.
+// Cost returns gas * gasPrice + value.
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
-	total.Add(total, tx.data.Amount)
+	total := new(big.Int).Mul(tx.inner.gasPrice(), new(big.Int).SetUint64(tx.Gas()))
+	total.Add(total, tx.inner.value())
 	return total
 }
 
-func (tx *Transaction) RawSignatureValues() (*big.Int, *big.Int, *big.Int) {
-	return tx.data.V, tx.data.R, tx.data.S
+// RawSignatureValues returns the V, R, S signature values of the transaction.
+// The return values should not be modified by the caller.
+func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
+	return tx.inner.rawSignatureValues()
 }
 
 // Transactions is a Transaction slice type for basic sorting.
@@ -316,9 +423,10 @@ func (s Transactions) Len() int { return len(s) }
 // Swap swaps the i'th and the j'th element in s.
 func (s Transactions) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
-// GetRlp implements Rlpable and returns the i'th element of s in rlp.
+// GetRlp implements DerivableList and returns the canonical encoding of the
+// i'th element of s, see Transaction.MarshalBinary.
 func (s Transactions) GetRlp(i int) []byte {
-	enc, _ := rlp.EncodeToBytes(s[i])
+	enc, _ := s[i].MarshalBinary()
 	return enc
 }
 
@@ -346,7 +454,7 @@ func TxDifference(a, b Transactions) Transactions {
 type TxByNonce Transactions
 
 func (s TxByNonce) Len() int           { return len(s) }
-func (s TxByNonce) Less(i, j int) bool { return s[i].data.AccountNonce < s[j].data.AccountNonce }
+func (s TxByNonce) Less(i, j int) bool { return s[i].Nonce() < s[j].Nonce() }
 func (s TxByNonce) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 
 // TxByPrice implements both the sort and the heap interface, making it useful
@@ -354,7 +462,7 @@ func (s TxByNonce) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 type TxByPrice Transactions
 
 func (s TxByPrice) Len() int           { return len(s) }
-func (s TxByPrice) Less(i, j int) bool { return s[i].data.Price.Cmp(s[j].data.Price) > 0 }
+func (s TxByPrice) Less(i, j int) bool { return s[i].inner.gasPrice().Cmp(s[j].inner.gasPrice()) > 0 }
 func (s TxByPrice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 
 func (s *TxByPrice) Push(x interface{}) {
@@ -441,11 +549,14 @@ type Message struct {
 	amount     *big.Int
 	gasLimit   uint64
 	gasPrice   *big.Int
+	gasFeeCap  *big.Int
+	gasTipCap  *big.Int
 	data       []byte
+	accessList AccessList
 	checkNonce bool
 }
 
-func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool) Message {
+func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice, gasFeeCap, gasTipCap *big.Int, data []byte, accessList AccessList, checkNonce bool) Message {
 	return Message{
 		from:       from,
 		to:         to,
@@ -453,21 +564,41 @@ func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *b
 		amount:     amount,
 		gasLimit:   gasLimit,
 		gasPrice:   gasPrice,
+		gasFeeCap:  gasFeeCap,
+		gasTipCap:  gasTipCap,
 		data:       data,
+		accessList: accessList,
 		checkNonce: checkNonce,
 	}
 }
 
-func (m Message) From() common.Address { return m.from }
-func (m Message) To() *common.Address  { return m.to }
-func (m Message) GasPrice() *big.Int   { return m.gasPrice }
-func (m Message) Value() *big.Int      { return m.amount }
-func (m Message) Gas() uint64          { return m.gasLimit }
-func (m Message) Nonce() uint64        { return m.nonce }
-func (m Message) Data() []byte         { return m.data }
-func (m Message) CheckNonce() bool     { return m.checkNonce }
-
-type messageRLP struct {
+func (m Message) From() common.Address   { return m.from }
+func (m Message) To() *common.Address    { return m.to }
+func (m Message) GasPrice() *big.Int     { return m.gasPrice }
+func (m Message) GasFeeCap() *big.Int    { return m.gasFeeCap }
+func (m Message) GasTipCap() *big.Int    { return m.gasTipCap }
+func (m Message) Value() *big.Int        { return m.amount }
+func (m Message) Gas() uint64            { return m.gasLimit }
+func (m Message) Nonce() uint64          { return m.nonce }
+func (m Message) Data() []byte           { return m.data }
+func (m Message) AccessList() AccessList { return m.accessList }
+func (m Message) CheckNonce() bool       { return m.checkNonce }
+
+// MessageEncodingVersion is the version of the Message RLP encoding written
+// by EncodeRLP.
+//
+// Version 0 messages are lists of the message fields, which store a nil
+// recipient as the zero address. Version 1 messages are prefixed by the
+// version number and store a nil recipient as an empty string, so that
+// transfers to the zero address survive a round trip. Version 2 messages
+// append the fee cap, the tip cap and the access list; messages of earlier
+// versions decode with both caps set to the gas price.
+const MessageEncodingVersion = 2
+
+var errMessageVersion = errors.New("unsupported message encoding version")
+
+// messageRLPv0 is the version 0 encoding of a message.
+type messageRLPv0 struct {
 	To         *common.Address
 	From       common.Address
 	Nonce      uint64
@@ -478,56 +609,161 @@ type messageRLP struct {
 	CheckNonce bool
 }
 
-var (
-	nilAddress = common.Address{}
-)
-
-func (m *Message) EncodeRLP(w io.Writer) error {
-	msg := messageRLP{}
-	msg.From = m.from
-	msg.Nonce = m.nonce
-	msg.Amount = m.amount
-	msg.GasLimit = m.gasLimit
-	msg.GasPrice = m.gasPrice
-	msg.Data = m.data
-	msg.CheckNonce = m.checkNonce
-	if m.to != nil {
-		msg.To = m.to
-	} else {
-		msg.To = &nilAddress
-	}
-	return rlp.Encode(w, &msg)
-
+// messageRLP is the current encoding of a message.
+type messageRLP struct {
+	Version    uint8
+	To         *common.Address `rlp:"nil"`
+	From       common.Address
+	Nonce      uint64
+	Amount     *big.Int
+	GasLimit   uint64
+	GasPrice   *big.Int
+	Data       []byte
+	CheckNonce bool
+	GasFeeCap  *big.Int   `rlp:"optional"`
+	GasTipCap  *big.Int   `rlp:"optional"`
+	AccessList AccessList `rlp:"optional"`
 }
 
-// DecodeRLP implements rlp.Decoder, and loads the consensus fields of a receipt
-// from an RLP stream.
+// EncodeRLP implements rlp.Encoder, writing the current message encoding.
+func (m *Message) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &messageRLP{
+		Version:    MessageEncodingVersion,
+		To:         m.to,
+		From:       m.from,
+		Nonce:      m.nonce,
+		Amount:     m.amount,
+		GasLimit:   m.gasLimit,
+		GasPrice:   m.gasPrice,
+		Data:       m.data,
+		CheckNonce: m.checkNonce,
+		GasFeeCap:  m.gasFeeCap,
+		GasTipCap:  m.gasTipCap,
+		AccessList: m.accessList,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder. It accepts all encoding versions; the
+// zero recipient of version 0 messages decodes as nil.
 func (m *Message) DecodeRLP(s *rlp.Stream) error {
-	var dec messageRLP
-	if err := s.Decode(&dec); err != nil {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	version, err := messageVersion(raw)
+	if err != nil {
 		return err
 	}
-	m.from, m.nonce, m.amount, m.gasLimit, m.gasPrice, m.data, m.checkNonce = dec.From, dec.Nonce, dec.Amount, dec.GasLimit, dec.GasPrice, dec.Data, dec.CheckNonce
-	if *dec.To != nilAddress {
-		m.to = dec.To
+	var dec messageRLP
+	switch version {
+	case 0:
+		var v0 messageRLPv0
+		if err := rlp.DecodeBytes(raw, &v0); err != nil {
+			return err
+		}
+		dec = messageRLP{To: v0.To, From: v0.From, Nonce: v0.Nonce, Amount: v0.Amount, GasLimit: v0.GasLimit, GasPrice: v0.GasPrice, Data: v0.Data, CheckNonce: v0.CheckNonce}
+		if *dec.To == (common.Address{}) {
+			dec.To = nil
+		}
+	case 1, MessageEncodingVersion:
+		if err := rlp.DecodeBytes(raw, &dec); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w %d", errMessageVersion, version)
+	}
+	if version < 2 {
+		dec.GasFeeCap, dec.GasTipCap, dec.AccessList = dec.GasPrice, dec.GasPrice, nil
+	}
+	*m = Message{
+		to:         dec.To,
+		from:       dec.From,
+		nonce:      dec.Nonce,
+		amount:     dec.Amount,
+		gasLimit:   dec.GasLimit,
+		gasPrice:   dec.GasPrice,
+		gasFeeCap:  dec.GasFeeCap,
+		gasTipCap:  dec.GasTipCap,
+		data:       dec.Data,
+		accessList: dec.AccessList,
+		checkNonce: dec.CheckNonce,
 	}
 	return nil
 }
 
-/* ===============================================*/
+// messageVersion returns the encoding version of an RLP encoded message.
+// Version 0 messages start with the 20 byte recipient, later versions with
+// the version number.
+func messageVersion(enc []byte) (uint64, error) {
+	content, _, err := rlp.SplitList(enc)
+	if err != nil {
+		return 0, err
+	}
+	kind, first, _, err := rlp.Split(content)
+	if err != nil {
+		return 0, err
+	}
+	if kind == rlp.String && len(first) == common.AddressLength {
+		return 0, nil
+	}
+	if kind == rlp.List || len(first) > 8 {
+		return 0, errMessageVersion
+	}
+	var version uint64
+	for _, b := range first {
+		version = version<<8 | uint64(b)
+	}
+	return version, nil
+}
+
+// MigrateMessage re-encodes an RLP encoded message of any version in the
+// current encoding.
+func MigrateMessage(enc []byte) ([]byte, error) {
+	var m Message
+	if err := rlp.DecodeBytes(enc, &m); err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(&m)
+}
+
+// Selector is the 4 byte function selector at the start of contract call
+// data.
+type Selector [4]byte
 
-// get the signature of an entry point
+// String returns the hex encoding of the selector.
+func (s Selector) String() string { return hexutil.Encode(s[:]) }
+
+// Selector returns the function selector of the message's call data. It
+// reports false if the data is shorter than a selector.
+func (m Message) Selector() (Selector, bool) {
+	var sel Selector
+	if len(m.data) < len(sel) {
+		return sel, false
+	}
+	copy(sel[:], m.data)
+	return sel, true
+}
+
+// EntrySignature returns the function selector of the message's call data
+// as a string of raw bytes, or "" if the data is too short.
+//
+// Deprecated: use Selector.
 func (m Message) EntrySignature() string {
-	if len(m.data) >= 4 {
-		return string(m.data[:4])
+	if sel, ok := m.Selector(); ok {
+		return string(sel[:])
 	}
 	return ""
 }
+
+// GobEncode implements gob.GobEncoder using the RLP encoding.
 func (m *Message) GobEncode() ([]byte, error) {
 	return rlp.EncodeToBytes(m)
 }
+
+// GobDecode implements gob.GobDecoder. Like DecodeRLP, it accepts all
+// encoding versions.
 func (m *Message) GobDecode(data []byte) error {
-	return rlp.DecodeBytes(data, &m)
+	return rlp.DecodeBytes(data, m)
 }
 
 func (m Message) Fee() *big.Int { return big.NewInt(0).Mul(big.NewInt(int64(m.gasLimit)), m.gasPrice) } // Max fee possible
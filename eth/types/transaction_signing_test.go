@@ -0,0 +1,119 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+)
+
+func makeSignedTxs(t testing.TB, signer Signer, n int) (Transactions, []common.Address) {
+	txs := make(Transactions, n)
+	senders := make([]common.Address, n)
+	keys := make([]string, 4)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%064x", i+1)
+	}
+	for i := range txs {
+		key, _ := crypto.HexToECDSA(keys[i%len(keys)])
+		tx, err := SignTx(NewTransaction(uint64(i), testAddr, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs[i], senders[i] = tx, crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return txs, senders
+}
+
+func TestRecoverSenders(t *testing.T) {
+	signer := NewEIP155Signer(big.NewInt(1))
+	for _, workers := range []int{0, 1, 3, 100} {
+		txs, want := makeSignedTxs(t, signer, 50)
+		senders, errs := RecoverSenders(signer, txs, workers)
+		if errs != nil {
+			t.Fatalf("workers=%d: unexpected errors %v", workers, errs)
+		}
+		for i := range txs {
+			if senders[i] != want[i] {
+				t.Errorf("workers=%d: tx %d: wrong sender %x", workers, i, senders[i])
+			}
+			// The sender cache must be filled.
+			if sc := txs[i].from.Load(); sc == nil || sc.(sigCache).from != want[i] {
+				t.Errorf("workers=%d: tx %d: sender not cached", workers, i)
+			}
+		}
+	}
+}
+
+func TestRecoverSendersErrors(t *testing.T) {
+	signer := NewEIP155Signer(big.NewInt(1))
+	txs, want := makeSignedTxs(t, signer, 10)
+	// Signed for another chain.
+	other, _ := makeSignedTxs(t, NewEIP155Signer(big.NewInt(2)), 1)
+	txs[3] = other[0]
+	// Invalid signature values.
+	txs[7], _ = NewTransaction(7, testAddr, big.NewInt(1), 21000, big.NewInt(1), nil).WithSignature(signer, make([]byte, 65))
+
+	senders, errs := RecoverSenders(signer, txs, 4)
+	if errs == nil {
+		t.Fatal("expected errors")
+	}
+	for i := range txs {
+		switch i {
+		case 3:
+			if errs[i] != ErrInvalidChainId {
+				t.Errorf("tx %d: got error %v, want ErrInvalidChainId", i, errs[i])
+			}
+		case 7:
+			if errs[i] != ErrInvalidSig {
+				t.Errorf("tx %d: got error %v, want ErrInvalidSig", i, errs[i])
+			}
+		default:
+			if errs[i] != nil || senders[i] != want[i] {
+				t.Errorf("tx %d: got sender %x, error %v", i, senders[i], errs[i])
+			}
+		}
+	}
+}
+
+func BenchmarkRecoverSenders(b *testing.B) {
+	signer := NewEIP155Signer(big.NewInt(1))
+	txs, _ := makeSignedTxs(b, signer, 1000)
+	counts := []int{1, 2, 4, 8, 16}
+	if n := runtime.NumCPU(); n > 16 || n&(n-1) != 0 {
+		counts = append(counts, n)
+	}
+	for _, workers := range counts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				// Copy the transactions to start with empty sender caches.
+				cpy := make(Transactions, len(txs))
+				for j, tx := range txs {
+					cpy[j] = &Transaction{inner: tx.inner}
+				}
+				b.StartTimer()
+				RecoverSenders(signer, cpy, workers)
+			}
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package types
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"math/big"
+	"reflect"
 	"testing"
 
 	ethCommon "github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+	"github.com/arcology-network/3rd-party/eth/params"
 	ethRlp "github.com/arcology-network/3rd-party/eth/rlp"
 )
 
@@ -19,9 +26,413 @@ func BenchmarkTransactionAsMessage(b *testing.B) {
 				b.Error(err)
 			}
 			ethCommon.RlpHash(&tx)
-			if _, err := tx.AsMessage(NewEIP155Signer(new(big.Int).SetInt64(1))); err != nil {
+			if _, err := tx.AsMessage(NewEIP155Signer(new(big.Int).SetInt64(1)), nil); err != nil {
 				b.Error(err)
 			}
 		}
 	}
 }
+
+func TestTransactionDecodeStrict(t *testing.T) {
+	canon := ethCommon.FromHex("f86a8086d55698372431831e848094f0109fc8df283027b6285cc889f5aa624eac1f55843b9aca008025a009ebb6ca057a0535d6186462bc0b465b561c94a295bdb0621fc19208ab149a9ca0440ffd775ce91a833ab410777204d5341a6f9fa91216a6f3ee2c051fea6a0428")
+	var tx Transaction
+	if err := ethRlp.DecodeBytesStrict(canon, &tx); err != nil {
+		t.Fatalf("canonical transaction rejected: %v", err)
+	}
+	if tx.Size() != 108 {
+		t.Errorf("wrong size %v", tx.Size())
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		offset int
+		err    error
+	}{
+		{
+			name:   "nonce wrapped as string",
+			input:  "f86b810086d55698372431831e848094f0109fc8df283027b6285cc889f5aa624eac1f55843b9aca008025a009ebb6ca057a0535d6186462bc0b465b561c94a295bdb0621fc19208ab149a9ca0440ffd775ce91a833ab410777204d5341a6f9fa91216a6f3ee2c051fea6a0428",
+			offset: 2,
+			err:    ethRlp.ErrCanonSize,
+		},
+		{
+			name:   "nonce with leading zero",
+			input:  "f86c82000186d55698372431831e848094f0109fc8df283027b6285cc889f5aa624eac1f55843b9aca008025a009ebb6ca057a0535d6186462bc0b465b561c94a295bdb0621fc19208ab149a9ca0440ffd775ce91a833ab410777204d5341a6f9fa91216a6f3ee2c051fea6a0428",
+			offset: 2,
+			err:    ethRlp.ErrCanonInt,
+		},
+		{
+			name:   "typed transaction nonce with leading zero",
+			input:  "b86801f86501820003018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001a0c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521",
+			offset: 6,
+			err:    ethRlp.ErrCanonInt,
+		},
+	}
+	for _, test := range tests {
+		input := ethCommon.FromHex(test.input)
+		err := ethRlp.DecodeBytesStrict(input, new(Transaction))
+		var cerr *ethRlp.CanonError
+		if !errors.As(err, &cerr) {
+			t.Errorf("%s: got error %v, want *rlp.CanonError", test.name, err)
+			continue
+		}
+		if cerr.Offset != test.offset || cerr.Err != test.err {
+			t.Errorf("%s: wrong violation %v", test.name, cerr)
+		}
+		// Without strict mode, the input is still rejected, but the
+		// error does not carry an offset.
+		err = ethRlp.DecodeBytes(input, new(Transaction))
+		if err == nil || errors.As(err, &cerr) {
+			t.Errorf("%s: got error %v in non-strict mode", test.name, err)
+		}
+	}
+}
+
+var (
+	testAddr = ethCommon.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+
+	emptyEip2718Tx = NewTx(&AccessListTx{
+		ChainID:  big.NewInt(1),
+		Nonce:    3,
+		To:       &testAddr,
+		Value:    big.NewInt(10),
+		Gas:      25000,
+		GasPrice: big.NewInt(1),
+		Data:     ethCommon.FromHex("5544"),
+	})
+
+	signedEip2718Tx, _ = emptyEip2718Tx.WithSignature(
+		NewEIP2930Signer(big.NewInt(1)),
+		ethCommon.FromHex("c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b266032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d3752101"),
+	)
+)
+
+func TestEIP2718TransactionSigHash(t *testing.T) {
+	s := NewEIP2930Signer(big.NewInt(1))
+	want := ethCommon.HexToHash("49b486f0ec0a60dfbbca2d30cb07c9e8ffb2a2ff41f29a1ab6737475f6ff69f3")
+	if h := s.Hash(emptyEip2718Tx); h != want {
+		t.Errorf("empty EIP-2718 transaction hash mismatch, got %x", h)
+	}
+	if h := s.Hash(signedEip2718Tx); h != want {
+		t.Errorf("signed EIP-2718 transaction hash mismatch, got %x", h)
+	}
+}
+
+func TestEIP2718TransactionEncode(t *testing.T) {
+	// RLP representation
+	have, err := ethRlp.EncodeToBytes(signedEip2718Tx)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	want := ethCommon.FromHex("b86601f8630103018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001a0c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521")
+	if !bytes.Equal(have, want) {
+		t.Errorf("encoded RLP mismatch, got %x", have)
+	}
+
+	// Binary representation
+	have, err = signedEip2718Tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if !bytes.Equal(have, want[2:]) {
+		t.Errorf("encoded binary mismatch, got %x", have)
+	}
+
+	// Decoding both forms must yield the same transaction.
+	var fromRLP, fromBinary Transaction
+	if err := ethRlp.DecodeBytes(want, &fromRLP); err != nil {
+		t.Fatalf("RLP decode error: %v", err)
+	}
+	if err := fromBinary.UnmarshalBinary(want[2:]); err != nil {
+		t.Fatalf("binary decode error: %v", err)
+	}
+	for _, tx := range []*Transaction{&fromRLP, &fromBinary} {
+		if tx.Hash() != signedEip2718Tx.Hash() || tx.Type() != AccessListTxType {
+			t.Errorf("decoded transaction mismatch: type %d hash %x", tx.Type(), tx.Hash())
+		}
+	}
+	if err := fromBinary.UnmarshalBinary([]byte{0x7f, 0xc0}); err != ErrTxTypeNotSupported {
+		t.Errorf("wrong error for unknown type: %v", err)
+	}
+}
+
+func testSignedTxs(t *testing.T) (ethCommon.Address, Signer, []*Transaction) {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewLondonSigner(big.NewInt(1))
+	accesses := AccessList{{Address: testAddr, StorageKeys: []ethCommon.Hash{{0}, {1}}}}
+	txdata := []TxData{
+		&LegacyTx{Nonce: 1, To: &testAddr, Value: big.NewInt(10), Gas: 21000, GasPrice: big.NewInt(2), Data: []byte("abcdef")},
+		&LegacyTx{Nonce: 2, Value: big.NewInt(10), Gas: 53000, GasPrice: big.NewInt(2), Data: []byte("abcdef")},
+		&AccessListTx{ChainID: big.NewInt(1), Nonce: 3, To: &testAddr, Gas: 30000, GasPrice: big.NewInt(3), AccessList: accesses},
+		&DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 4, To: &testAddr, Gas: 30000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(5), Value: big.NewInt(7), AccessList: accesses},
+		&DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 5, Gas: 60000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(5), Data: []byte{1, 2, 3}},
+	}
+	var txs []*Transaction
+	for _, data := range txdata {
+		tx, err := SignNewTx(key, signer, data)
+		if err != nil {
+			t.Fatalf("could not sign transaction: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	return crypto.PubkeyToAddress(key.PublicKey), signer, txs
+}
+
+func TestTransactionCoding(t *testing.T) {
+	from, signer, txs := testSignedTxs(t)
+	for i, tx := range txs {
+		// RLP roundtrip.
+		enc, err := ethRlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatalf("tx %d: encode error: %v", i, err)
+		}
+		parsed := new(Transaction)
+		if err := ethRlp.DecodeBytes(enc, parsed); err != nil {
+			t.Fatalf("tx %d: decode error: %v", i, err)
+		}
+		assertEqual(t, i, parsed, tx)
+		if parsed.Size() != ethCommon.StorageSize(len(enc)) && tx.Type() == LegacyTxType {
+			t.Errorf("tx %d: wrong size %v, want %d", i, parsed.Size(), len(enc))
+		}
+
+		// JSON roundtrip.
+		data, err := json.Marshal(tx)
+		if err != nil {
+			t.Fatalf("tx %d: json encode error: %v", i, err)
+		}
+		parsed = new(Transaction)
+		if err := json.Unmarshal(data, parsed); err != nil {
+			t.Fatalf("tx %d: json decode error: %v", i, err)
+		}
+		assertEqual(t, i, parsed, tx)
+
+		// The sender must be recoverable from the decoded transaction.
+		sender, err := Sender(signer, parsed)
+		if err != nil {
+			t.Fatalf("tx %d: sender error: %v", i, err)
+		}
+		if sender != from {
+			t.Errorf("tx %d: wrong sender %x, want %x", i, sender, from)
+		}
+	}
+}
+
+func TestSignerTxTypes(t *testing.T) {
+	_, _, txs := testSignedTxs(t)
+	signers := []struct {
+		signer Signer
+		types  []uint8
+	}{
+		{HomesteadSigner{}, []uint8{}},
+		{NewEIP155Signer(big.NewInt(1)), []uint8{LegacyTxType}},
+		{NewEIP2930Signer(big.NewInt(1)), []uint8{LegacyTxType, AccessListTxType}},
+		{NewLondonSigner(big.NewInt(1)), []uint8{LegacyTxType, AccessListTxType, DynamicFeeTxType}},
+	}
+	for _, s := range signers {
+		supported := make(map[uint8]bool)
+		for _, typ := range s.types {
+			supported[typ] = true
+		}
+		for i, tx := range txs {
+			_, err := s.signer.Sender(tx)
+			switch {
+			case supported[tx.Type()] && err != nil:
+				t.Errorf("%T: tx %d: unexpected error %v", s.signer, i, err)
+			case tx.Type() != LegacyTxType && !supported[tx.Type()] && err != ErrTxTypeNotSupported:
+				t.Errorf("%T: tx %d: got error %v, want ErrTxTypeNotSupported", s.signer, i, err)
+			}
+		}
+	}
+	if _, err := NewLondonSigner(big.NewInt(2)).Sender(txs[3]); err != ErrInvalidChainId {
+		t.Errorf("wrong error for chain ID mismatch: %v", err)
+	}
+}
+
+func assertEqual(t *testing.T, i int, have, want *Transaction) {
+	t.Helper()
+	if have.Hash() != want.Hash() {
+		t.Errorf("tx %d: hash mismatch: have %x, want %x", i, have.Hash(), want.Hash())
+	}
+	if have.Type() != want.Type() || have.ChainId().Cmp(want.ChainId()) != 0 {
+		t.Errorf("tx %d: type/chain mismatch: have %d/%v, want %d/%v", i, have.Type(), have.ChainId(), want.Type(), want.ChainId())
+	}
+	if !reflect.DeepEqual(have.AccessList(), want.AccessList()) {
+		t.Errorf("tx %d: access list mismatch: have %v, want %v", i, have.AccessList(), want.AccessList())
+	}
+}
+
+func TestMakeSigner(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID:        big.NewInt(1),
+		HomesteadBlock: big.NewInt(1),
+		EIP155Block:    big.NewInt(2),
+		BerlinBlock:    big.NewInt(3),
+		LondonBlock:    big.NewInt(4),
+	}
+	tests := []struct {
+		number int64
+		want   Signer
+	}{
+		{0, FrontierSigner{}},
+		{1, HomesteadSigner{}},
+		{2, NewEIP155Signer(config.ChainID)},
+		{3, NewEIP2930Signer(config.ChainID)},
+		{4, NewLondonSigner(config.ChainID)},
+		{100, NewLondonSigner(config.ChainID)},
+	}
+	for _, test := range tests {
+		if s := MakeSigner(config, big.NewInt(test.number)); !s.Equal(test.want) {
+			t.Errorf("block %d: got %T, want %T", test.number, s, test.want)
+		}
+	}
+	if s := LatestSigner(config); !s.Equal(NewLondonSigner(config.ChainID)) {
+		t.Errorf("LatestSigner returned %T", s)
+	}
+	if s := LatestSigner(params.TestChainConfig); !s.Equal(NewLondonSigner(params.TestChainConfig.ChainID)) {
+		t.Errorf("LatestSigner(TestChainConfig) returned %T", s)
+	}
+}
+
+func TestMessageEncoding(t *testing.T) {
+	from := ethCommon.HexToAddress("0x1234")
+	recipient := ethCommon.HexToAddress("0xabcd")
+	for _, to := range []*ethCommon.Address{nil, {}, &recipient} {
+		msg := NewMessage(from, to, 3, big.NewInt(5), 21000, big.NewInt(7), big.NewInt(9), big.NewInt(2), []byte{0xa9, 0x05, 0x9c, 0xbb, 1}, AccessList{{Address: recipient, StorageKeys: []ethCommon.Hash{{1}}}}, true)
+
+		enc, err := ethRlp.EncodeToBytes(&msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dec Message
+		if err := ethRlp.DecodeBytes(enc, &dec); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(dec, msg) {
+			t.Errorf("to %v: RLP round trip got %+v, want %+v", to, dec, msg)
+		}
+
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(&msg); err != nil {
+			t.Fatal(err)
+		}
+		var gobDec Message
+		if err := gob.NewDecoder(&buf).Decode(&gobDec); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(gobDec, msg) {
+			t.Errorf("to %v: gob round trip got %+v, want %+v", to, gobDec, msg)
+		}
+	}
+}
+
+func TestAsMessage(t *testing.T) {
+	from, signer, txs := testSignedTxs(t)
+	for i, test := range []struct {
+		tx      *Transaction
+		baseFee *big.Int
+		price   int64
+	}{
+		{txs[0], nil, 2},
+		{txs[0], big.NewInt(1), 2},
+		{txs[2], big.NewInt(1), 3},
+		{txs[3], nil, 5},
+		{txs[3], big.NewInt(2), 3},
+		{txs[3], big.NewInt(10), 5},
+	} {
+		msg, err := test.tx.AsMessage(signer, test.baseFee)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if msg.From() != from || msg.GasPrice().Int64() != test.price {
+			t.Errorf("test %d: from %x, gas price %v, want %x, %d", i, msg.From(), msg.GasPrice(), from, test.price)
+		}
+		if msg.GasFeeCap().Cmp(test.tx.GasFeeCap()) != 0 || msg.GasTipCap().Cmp(test.tx.GasTipCap()) != 0 {
+			t.Errorf("test %d: fee cap %v, tip cap %v", i, msg.GasFeeCap(), msg.GasTipCap())
+		}
+		if !reflect.DeepEqual(msg.AccessList(), test.tx.AccessList()) {
+			t.Errorf("test %d: access list %v, want %v", i, msg.AccessList(), test.tx.AccessList())
+		}
+	}
+	// The message must not share the big integers of the transaction.
+	msg, _ := txs[3].AsMessage(signer, big.NewInt(2))
+	msg.GasPrice().SetInt64(100)
+	if txs[3].GasTipCap().Int64() != 1 || txs[3].GasFeeCap().Int64() != 5 {
+		t.Error("message aliases the transaction fee fields")
+	}
+}
+
+func TestMessageMigration(t *testing.T) {
+	recipient := ethCommon.HexToAddress("0xabcd")
+	for _, test := range []struct {
+		to   ethCommon.Address
+		want *ethCommon.Address
+	}{
+		// Version 0 cannot tell contract creation from the zero address.
+		{ethCommon.Address{}, nil},
+		{recipient, &recipient},
+	} {
+		legacy, err := ethRlp.EncodeToBytes(&messageRLPv0{
+			To:       &test.to,
+			From:     ethCommon.HexToAddress("0x1234"),
+			Nonce:    1,
+			Amount:   big.NewInt(2),
+			GasLimit: 3,
+			GasPrice: big.NewInt(4),
+			Data:     []byte{5},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msg Message
+		if err := ethRlp.DecodeBytes(legacy, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(msg.To(), test.want) || msg.Nonce() != 1 || msg.Value().Int64() != 2 {
+			t.Errorf("to %x: decoded %+v", test.to, msg)
+		}
+		migrated, err := MigrateMessage(legacy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := ethRlp.EncodeToBytes(&msg); !bytes.Equal(migrated, want) {
+			t.Errorf("to %x: migrated to %x, want %x", test.to, migrated, want)
+		}
+		if v, _ := messageVersion(migrated); v != MessageEncodingVersion {
+			t.Errorf("migrated message has version %d", v)
+		}
+	}
+
+	// Version 1 messages carry no fee caps and are priced like legacy
+	// transactions.
+	v1, _ := ethRlp.EncodeToBytes([]interface{}{uint(1), []byte{}, ethCommon.Address{}, uint(0), uint(0), uint(21000), uint(4), []byte{}, false})
+	var msg Message
+	if err := ethRlp.DecodeBytes(v1, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.To() != nil || msg.GasFeeCap().Int64() != 4 || msg.GasTipCap().Int64() != 4 || msg.AccessList() != nil {
+		t.Errorf("version 1 message decoded as %+v", msg)
+	}
+
+	future, _ := ethRlp.EncodeToBytes([]interface{}{uint(MessageEncodingVersion + 1), []byte{}})
+	if err := ethRlp.DecodeBytes(future, new(Message)); !errors.Is(err, errMessageVersion) {
+		t.Errorf("got error %v, want %v", err, errMessageVersion)
+	}
+}
+
+func TestMessageSelector(t *testing.T) {
+	msg := NewMessage(ethCommon.Address{}, nil, 0, big.NewInt(0), 0, big.NewInt(0), nil, nil, ethCommon.FromHex("a9059cbb0000"), nil, false)
+	sel, ok := msg.Selector()
+	if !ok || sel != (Selector{0xa9, 0x05, 0x9c, 0xbb}) || sel.String() != "0xa9059cbb" {
+		t.Errorf("got selector %v, %v", sel, ok)
+	}
+	if msg.EntrySignature() != string(sel[:]) {
+		t.Errorf("EntrySignature disagrees with Selector")
+	}
+	msg = NewMessage(ethCommon.Address{}, nil, 0, big.NewInt(0), 0, big.NewInt(0), nil, nil, []byte{1, 2, 3}, nil, false)
+	if _, ok := msg.Selector(); ok || msg.EntrySignature() != "" {
+		t.Error("selector returned for short data")
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"container/heap"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+)
+
+var (
+	// ErrAlreadyKnown is returned by PricedTxPool.Add if the transaction is
+	// already in the pool.
+	ErrAlreadyKnown = errors.New("already known")
+
+	// ErrReplaceUnderpriced is returned by PricedTxPool.Add if a transaction
+	// with the same sender and nonce exists and the new one does not raise
+	// its price by the required bump.
+	ErrReplaceUnderpriced = errors.New("replacement transaction underpriced")
+
+	// ErrUnderpriced is returned by PricedTxPool.Add if the pool is full and
+	// the transaction is not more expensive than the cheapest one in it.
+	ErrUnderpriced = errors.New("transaction underpriced")
+)
+
+// poolTx is a transaction in a PricedTxPool.
+type poolTx struct {
+	tx    *Transaction
+	from  common.Address
+	index int // position in the eviction heap
+}
+
+// PricedTxPool holds transactions ordered by nonce per sender and by price
+// across senders. Unlike TransactionsByPriceAndNonce, it is maintained
+// incrementally: transactions can be added, replaced by a higher priced one
+// with the same nonce, and removed at any time. When the pool holds its
+// capacity, adding a transaction evicts the cheapest one.
+//
+// PricedTxPool is not safe for concurrent use.
+type PricedTxPool struct {
+	signer    Signer
+	capacity  int    // maximum number of transactions, unbounded if <= 0
+	priceBump uint64 // minimum price increase of replacements in percent
+
+	all      map[common.Hash]*poolTx
+	accounts map[common.Address]map[uint64]*poolTx
+	cheapest evictHeap
+}
+
+// NewPricedTxPool creates an empty pool holding up to capacity transactions.
+// A capacity of zero or less leaves the pool unbounded, so nothing is ever
+// evicted and Add never fails with ErrUnderpriced. Replacements must raise
+// the gas price, and for dynamic fee transactions also the tip, by at least
+// priceBump percent.
+func NewPricedTxPool(signer Signer, capacity int, priceBump uint64) *PricedTxPool {
+	return &PricedTxPool{
+		signer:    signer,
+		capacity:  capacity,
+		priceBump: priceBump,
+		all:       make(map[common.Hash]*poolTx),
+		accounts:  make(map[common.Address]map[uint64]*poolTx),
+	}
+}
+
+// Len returns the number of transactions in the pool.
+func (p *PricedTxPool) Len() int { return len(p.all) }
+
+// Has reports whether the pool contains the transaction with the given hash.
+func (p *PricedTxPool) Has(hash common.Hash) bool {
+	_, ok := p.all[hash]
+	return ok
+}
+
+// Get returns the transaction with the given hash, or nil if it is not in
+// the pool.
+func (p *PricedTxPool) Get(hash common.Hash) *Transaction {
+	if ptx := p.all[hash]; ptx != nil {
+		return ptx.tx
+	}
+	return nil
+}
+
+// Add inserts tx into the pool. If tx replaces a transaction with the same
+// sender and nonce or causes the cheapest transaction to be evicted, the
+// removed transaction is returned.
+func (p *PricedTxPool) Add(tx *Transaction) (dropped *Transaction, err error) {
+	if p.Has(tx.Hash()) {
+		return nil, ErrAlreadyKnown
+	}
+	from, err := Sender(p.signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	if old := p.accounts[from][tx.Nonce()]; old != nil {
+		if !p.isBumped(old.tx, tx) {
+			return nil, ErrReplaceUnderpriced
+		}
+		p.remove(old)
+		p.insert(&poolTx{tx: tx, from: from})
+		return old.tx, nil
+	}
+	if p.capacity > 0 && len(p.all) >= p.capacity {
+		victim := p.cheapest[0]
+		if !victim.less(tx) {
+			return nil, ErrUnderpriced
+		}
+		p.remove(victim)
+		dropped = victim.tx
+	}
+	p.insert(&poolTx{tx: tx, from: from})
+	return dropped, nil
+}
+
+// isBumped reports whether tx is priced high enough to replace old.
+func (p *PricedTxPool) isBumped(old, tx *Transaction) bool {
+	bump := func(v *big.Int) *big.Int {
+		b := new(big.Int).Mul(v, new(big.Int).SetUint64(100+p.priceBump))
+		return b.Div(b, big.NewInt(100))
+	}
+	return tx.inner.gasFeeCap().Cmp(bump(old.inner.gasFeeCap())) >= 0 &&
+		tx.inner.gasTipCap().Cmp(bump(old.inner.gasTipCap())) >= 0
+}
+
+// Remove deletes the transaction with the given hash from the pool and
+// reports whether it was present.
+func (p *PricedTxPool) Remove(hash common.Hash) bool {
+	ptx := p.all[hash]
+	if ptx == nil {
+		return false
+	}
+	p.remove(ptx)
+	return true
+}
+
+func (p *PricedTxPool) insert(ptx *poolTx) {
+	p.all[ptx.tx.Hash()] = ptx
+	acc := p.accounts[ptx.from]
+	if acc == nil {
+		acc = make(map[uint64]*poolTx)
+		p.accounts[ptx.from] = acc
+	}
+	acc[ptx.tx.Nonce()] = ptx
+	heap.Push(&p.cheapest, ptx)
+}
+
+func (p *PricedTxPool) remove(ptx *poolTx) {
+	delete(p.all, ptx.tx.Hash())
+	acc := p.accounts[ptx.from]
+	delete(acc, ptx.tx.Nonce())
+	if len(acc) == 0 {
+		delete(p.accounts, ptx.from)
+	}
+	heap.Remove(&p.cheapest, ptx.index)
+}
+
+// Cheapest returns the transaction that is evicted next, or nil if the pool
+// is empty.
+func (p *PricedTxPool) Cheapest() *Transaction {
+	if len(p.cheapest) == 0 {
+		return nil
+	}
+	return p.cheapest[0].tx
+}
+
+// AccountTxs returns the transactions of the given sender sorted by nonce.
+func (p *PricedTxPool) AccountTxs(from common.Address) Transactions {
+	acc := p.accounts[from]
+	txs := make(Transactions, 0, len(acc))
+	for _, ptx := range acc {
+		txs = append(txs, ptx.tx)
+	}
+	sort.Sort(TxByNonce(txs))
+	return txs
+}
+
+// Gaps returns the nonces missing from the transactions of the given sender,
+// starting at next, the sender's next executable nonce. Transactions after
+// the first gap cannot be executed until it is filled.
+func (p *PricedTxPool) Gaps(from common.Address, next uint64) []uint64 {
+	var gaps []uint64
+	for _, tx := range p.AccountTxs(from) {
+		for ; next < tx.Nonce(); next++ {
+			gaps = append(gaps, next)
+		}
+		if tx.Nonce() >= next {
+			next = tx.Nonce() + 1
+		}
+	}
+	return gaps
+}
+
+// Executable returns the transactions which can be executed in order, given
+// the next nonce of each sender. Senders missing from nonces start at their
+// lowest pooled nonce. For every sender, the transactions from its next
+// nonce up to the first gap are included and the result yields them in
+// price order across senders, like NewTransactionsByPriceAndNonce.
+func (p *PricedTxPool) Executable(nonces map[common.Address]uint64) *TransactionsByPriceAndNonce {
+	ready := make(map[common.Address]Transactions)
+	for from := range p.accounts {
+		txs := p.AccountTxs(from)
+		next, ok := nonces[from]
+		if !ok {
+			next = txs[0].Nonce()
+		}
+		var run Transactions
+		for _, tx := range txs {
+			if tx.Nonce() < next {
+				continue
+			}
+			if tx.Nonce() != next {
+				break
+			}
+			run = append(run, tx)
+			next++
+		}
+		if len(run) > 0 {
+			ready[from] = run
+		}
+	}
+	return NewTransactionsByPriceAndNonce(p.signer, ready)
+}
+
+// less reports whether ptx is evicted before tx. Cheaper transactions go
+// first and among equally priced ones the highest nonce goes first, so that
+// eviction does not open nonce gaps needlessly.
+func (ptx *poolTx) less(tx *Transaction) bool {
+	if c := ptx.tx.inner.gasPrice().Cmp(tx.inner.gasPrice()); c != 0 {
+		return c < 0
+	}
+	return ptx.tx.Nonce() > tx.Nonce()
+}
+
+// evictHeap is a min-heap of pooled transactions in eviction order.
+type evictHeap []*poolTx
+
+func (h evictHeap) Len() int           { return len(h) }
+func (h evictHeap) Less(i, j int) bool { return h[i].less(h[j].tx) }
+func (h evictHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *evictHeap) Push(x interface{}) {
+	ptx := x.(*poolTx)
+	ptx.index = len(*h)
+	*h = append(*h, ptx)
+}
+
+func (h *evictHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	ptx := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return ptx
+}
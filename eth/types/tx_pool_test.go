@@ -0,0 +1,319 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/crypto"
+)
+
+var poolSigner = NewEIP155Signer(big.NewInt(1))
+
+func poolKeys(n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		keys[i], _ = crypto.HexToECDSA(fmt.Sprintf("%064x", i+1))
+	}
+	return keys
+}
+
+func signPoolTx(t testing.TB, key *ecdsa.PrivateKey, nonce, price uint64) *Transaction {
+	tx, err := SignTx(NewTransaction(nonce, testAddr, big.NewInt(1), 21000, new(big.Int).SetUint64(price), nil), poolSigner, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestPricedTxPoolReplace(t *testing.T) {
+	key := poolKeys(1)[0]
+	pool := NewPricedTxPool(poolSigner, 10, 10)
+
+	old := signPoolTx(t, key, 0, 100)
+	if _, err := pool.Add(old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Add(old); err != ErrAlreadyKnown {
+		t.Fatalf("got %v, want %v", err, ErrAlreadyKnown)
+	}
+	if _, err := pool.Add(signPoolTx(t, key, 0, 109)); err != ErrReplaceUnderpriced {
+		t.Fatalf("got %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	replacement := signPoolTx(t, key, 0, 110)
+	dropped, err := pool.Add(replacement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != old {
+		t.Fatalf("dropped %v, want replaced transaction", dropped)
+	}
+	if pool.Len() != 1 || pool.Has(old.Hash()) || pool.Get(replacement.Hash()) != replacement {
+		t.Fatal("replacement not reflected in pool")
+	}
+}
+
+func TestPricedTxPoolEvict(t *testing.T) {
+	keys := poolKeys(2)
+	pool := NewPricedTxPool(poolSigner, 3, 10)
+	a0, a1 := signPoolTx(t, keys[0], 0, 5), signPoolTx(t, keys[0], 1, 5)
+	b0 := signPoolTx(t, keys[1], 0, 7)
+	for _, tx := range []*Transaction{a0, a1, b0} {
+		if _, err := pool.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := pool.Add(signPoolTx(t, keys[1], 1, 5)); err != ErrUnderpriced {
+		t.Fatalf("got %v, want %v", err, ErrUnderpriced)
+	}
+	// Of equally priced transactions, the highest nonce is evicted first.
+	if dropped, err := pool.Add(signPoolTx(t, keys[1], 1, 6)); err != nil || dropped != a1 {
+		t.Fatalf("dropped %v, err %v, want a1", dropped, err)
+	}
+	if dropped, err := pool.Add(signPoolTx(t, keys[1], 2, 8)); err != nil || dropped != a0 {
+		t.Fatalf("dropped %v, err %v, want a0", dropped, err)
+	}
+	if pool.Len() != 3 || pool.Cheapest().GasPrice().Uint64() != 6 {
+		t.Fatalf("unexpected pool state: len %d, cheapest %v", pool.Len(), pool.Cheapest().GasPrice())
+	}
+}
+
+func TestPricedTxPoolUnbounded(t *testing.T) {
+	keys := poolKeys(4)
+	for _, capacity := range []int{0, -1} {
+		pool := NewPricedTxPool(poolSigner, capacity, 10)
+		for i, key := range keys {
+			// Every new transaction is cheaper than all pooled ones.
+			if dropped, err := pool.Add(signPoolTx(t, key, 0, uint64(len(keys)-i))); err != nil || dropped != nil {
+				t.Fatalf("capacity %d: dropped %v, err %v", capacity, dropped, err)
+			}
+		}
+		if pool.Len() != len(keys) {
+			t.Errorf("capacity %d: pool holds %d transactions, want %d", capacity, pool.Len(), len(keys))
+		}
+	}
+}
+
+func TestPricedTxPoolGaps(t *testing.T) {
+	keys := poolKeys(2)
+	from := crypto.PubkeyToAddress(keys[0].PublicKey)
+	pool := NewPricedTxPool(poolSigner, 0, 10)
+	for _, nonce := range []uint64{3, 4, 6, 9} {
+		pool.Add(signPoolTx(t, keys[0], nonce, 1))
+	}
+	pool.Add(signPoolTx(t, keys[1], 0, 2))
+
+	for _, test := range []struct {
+		next uint64
+		want []uint64
+	}{
+		{0, []uint64{0, 1, 2, 5, 7, 8}},
+		{3, []uint64{5, 7, 8}},
+		{5, []uint64{5, 7, 8}},
+		{10, nil},
+	} {
+		if got := pool.Gaps(from, test.next); fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("next %d: got gaps %v, want %v", test.next, got, test.want)
+		}
+	}
+
+	var order []uint64
+	set := pool.Executable(map[common.Address]uint64{from: 3})
+	for tx := set.Peek(); tx != nil; tx = set.Peek() {
+		order = append(order, tx.Nonce())
+		set.Shift()
+	}
+	if fmt.Sprint(order) != "[0 3 4]" {
+		t.Errorf("executable nonces %v, want [0 3 4]", order)
+	}
+}
+
+// TestPricedTxPoolProperties applies random operations to a pool and checks
+// the outcome of each against a model and the pool invariants afterwards.
+func TestPricedTxPoolProperties(t *testing.T) {
+	const (
+		accounts = 4
+		nonces   = 6
+		capacity = 12
+	)
+	keys := poolKeys(accounts)
+	prices := []uint64{1, 2, 4}
+	var txs Transactions
+	for _, key := range keys {
+		for nonce := uint64(0); nonce < nonces; nonce++ {
+			for _, price := range prices {
+				txs = append(txs, signPoolTx(t, key, nonce, price))
+			}
+		}
+	}
+	type slot struct {
+		from  common.Address
+		nonce uint64
+	}
+	slotOf := func(tx *Transaction) slot {
+		from, _ := Sender(poolSigner, tx)
+		return slot{from, tx.Nonce()}
+	}
+
+	property := func(ops []uint16) bool {
+		pool := NewPricedTxPool(poolSigner, capacity, 50)
+		model := make(map[slot]*Transaction)
+		minPrice := func() uint64 {
+			min := ^uint64(0)
+			for _, tx := range model {
+				if p := tx.GasPrice().Uint64(); p < min {
+					min = p
+				}
+			}
+			return min
+		}
+		for _, op := range ops {
+			tx := txs[int(op>>2)%len(txs)]
+			s := slotOf(tx)
+			if op&3 == 3 {
+				if pool.Remove(tx.Hash()) != (model[s] == tx) {
+					t.Log("remove result disagrees with model")
+					return false
+				}
+				if model[s] == tx {
+					delete(model, s)
+				}
+			} else {
+				old, full := model[s], len(model) == capacity
+				dropped, err := pool.Add(tx)
+				switch {
+				case old == tx:
+					if err != ErrAlreadyKnown {
+						t.Log("expected ErrAlreadyKnown, got", err)
+						return false
+					}
+				case old != nil:
+					bumped := tx.GasPrice().Uint64()*100 >= old.GasPrice().Uint64()*150
+					if bumped != (err == nil) || (err == nil && dropped != old) || (err != nil && err != ErrReplaceUnderpriced) {
+						t.Log("unexpected replacement outcome", err)
+						return false
+					}
+					if err == nil {
+						model[s] = tx
+					}
+				case full:
+					min, evictable := minPrice(), false
+					for _, pooled := range model {
+						p := pooled.GasPrice().Uint64()
+						if p < tx.GasPrice().Uint64() || (p == tx.GasPrice().Uint64() && pooled.Nonce() > tx.Nonce()) {
+							evictable = true
+						}
+					}
+					if !evictable {
+						if err != ErrUnderpriced {
+							t.Log("expected ErrUnderpriced, got", err)
+							return false
+						}
+						continue
+					}
+					if err != nil || dropped == nil || dropped.GasPrice().Uint64() != min || model[slotOf(dropped)] != dropped {
+						t.Log("unexpected eviction", err)
+						return false
+					}
+					delete(model, slotOf(dropped))
+					model[s] = tx
+				default:
+					if err != nil || dropped != nil {
+						t.Log("unexpected add outcome", err)
+						return false
+					}
+					model[s] = tx
+				}
+			}
+			if !checkPoolInvariants(t, pool, len(model), minPrice()) {
+				return false
+			}
+		}
+		for s, tx := range model {
+			if pool.Get(tx.Hash()) != tx || pool.AccountTxs(s.from)[0] == nil {
+				t.Log("pool lost a transaction")
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 200}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkPoolInvariants(t *testing.T, pool *PricedTxPool, size int, minPrice uint64) bool {
+	if pool.Len() != size || size > pool.capacity {
+		t.Logf("pool size %d, want %d", pool.Len(), size)
+		return false
+	}
+	if size > 0 && pool.Cheapest().GasPrice().Uint64() != minPrice {
+		t.Logf("cheapest price %v, want %d", pool.Cheapest().GasPrice(), minPrice)
+		return false
+	}
+	total, executable := 0, 0
+	for from := range pool.accounts {
+		txs := pool.AccountTxs(from)
+		total += len(txs)
+		for i := 1; i < len(txs); i++ {
+			if txs[i-1].Nonce() >= txs[i].Nonce() {
+				t.Log("account transactions not strictly nonce ordered")
+				return false
+			}
+		}
+		// Pooled nonces and gaps must exactly cover the range.
+		first, last := txs[0].Nonce(), txs[len(txs)-1].Nonce()
+		gaps := pool.Gaps(from, first)
+		if uint64(len(gaps)+len(txs)) != last-first+1 {
+			t.Log("gaps do not complement pooled nonces")
+			return false
+		}
+		if len(gaps) == 0 {
+			executable += len(txs)
+		} else {
+			executable += int(gaps[0] - first)
+		}
+	}
+	if total != size {
+		t.Log("account index out of sync")
+		return false
+	}
+	// Executable yields each sender's run in nonce order.
+	next := make(map[common.Address]uint64)
+	set := pool.Executable(nil)
+	n := 0
+	for tx := set.Peek(); tx != nil; tx = set.Peek() {
+		from, _ := Sender(poolSigner, tx)
+		if want, ok := next[from]; ok && tx.Nonce() != want {
+			t.Log("executable transactions out of nonce order")
+			return false
+		}
+		next[from] = tx.Nonce() + 1
+		set.Shift()
+		n++
+	}
+	if n != executable {
+		t.Logf("%d executable transactions, want %d", n, executable)
+		return false
+	}
+	return true
+}
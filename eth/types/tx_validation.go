@@ -0,0 +1,148 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/arcology-network/3rd-party/eth/params"
+)
+
+var (
+	// ErrIntrinsicGas is returned if the gas limit of a transaction is below
+	// its intrinsic gas.
+	ErrIntrinsicGas = errors.New("intrinsic gas too low")
+
+	// ErrGasUintOverflow is returned if the intrinsic gas of a transaction
+	// overflows uint64.
+	ErrGasUintOverflow = errors.New("gas uint64 overflow")
+
+	// ErrGasLimit is returned if the gas limit of a transaction exceeds the
+	// block gas limit.
+	ErrGasLimit = errors.New("exceeds block gas limit")
+
+	// ErrTipAboveFeeCap is returned if the tip cap of a transaction exceeds
+	// its fee cap.
+	ErrTipAboveFeeCap = errors.New("max priority fee per gas higher than max fee per gas")
+
+	// ErrTipVeryHigh and ErrFeeCapVeryHigh are returned if the tip or fee
+	// cap of a transaction do not fit in 256 bits.
+	ErrTipVeryHigh    = errors.New("max priority fee per gas higher than 2^256-1")
+	ErrFeeCapVeryHigh = errors.New("max fee per gas higher than 2^256-1")
+
+	// ErrFeeCapTooLow is returned if the fee cap of a transaction is below
+	// the base fee of the block.
+	ErrFeeCapTooLow = errors.New("max fee per gas less than block base fee")
+)
+
+// IntrinsicGas computes the gas a transaction uses before any code runs:
+// the base cost of a call or contract creation plus the cost of its data and
+// access list under the given rules.
+func IntrinsicGas(data []byte, accessList AccessList, isContractCreation bool, rules params.Rules) (uint64, error) {
+	gas := params.TxGas
+	if isContractCreation && rules.IsHomestead {
+		gas = params.TxGasContractCreation
+	}
+	if len(data) > 0 {
+		var nz uint64
+		for _, b := range data {
+			if b != 0 {
+				nz++
+			}
+		}
+		nonZeroGas := params.TxDataNonZeroGas
+		if rules.IsIstanbul {
+			nonZeroGas = params.TxDataNonZeroGasEIP2028
+		}
+		if (math.MaxUint64-gas)/nonZeroGas < nz {
+			return 0, ErrGasUintOverflow
+		}
+		gas += nz * nonZeroGas
+
+		z := uint64(len(data)) - nz
+		if (math.MaxUint64-gas)/params.TxDataZeroGas < z {
+			return 0, ErrGasUintOverflow
+		}
+		gas += z * params.TxDataZeroGas
+	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
+	}
+	return gas, nil
+}
+
+// TxValidationOptions holds the limits ValidateTransaction checks besides
+// the protocol rules. Zero values disable the respective check.
+type TxValidationOptions struct {
+	BaseFee *big.Int // base fee of the pending block
+	MinTip  *big.Int // minimum effective tip per gas, the gas price for legacy transactions
+	MaxGas  uint64   // block gas limit
+}
+
+// ValidateTransaction checks that tx can be included in a block under the
+// given rules: its type must be enabled, its fees well formed and its gas
+// limit must cover the intrinsic gas. It is meant to reject transactions
+// early, before they are pooled; it does not check the sender's nonce or
+// balance.
+func ValidateTransaction(tx *Transaction, rules params.Rules, opts TxValidationOptions) error {
+	switch tx.Type() {
+	case AccessListTxType:
+		if !rules.IsBerlin {
+			return ErrTxTypeNotSupported
+		}
+	case DynamicFeeTxType:
+		if !rules.IsLondon {
+			return ErrTxTypeNotSupported
+		}
+	}
+	if opts.MaxGas != 0 && tx.Gas() > opts.MaxGas {
+		return fmt.Errorf("%w: gas %d, limit %d", ErrGasLimit, tx.Gas(), opts.MaxGas)
+	}
+
+	feeCap, tipCap := tx.inner.gasFeeCap(), tx.inner.gasTipCap()
+	if feeCap.BitLen() > 256 {
+		return ErrFeeCapVeryHigh
+	}
+	if tipCap.BitLen() > 256 {
+		return ErrTipVeryHigh
+	}
+	if feeCap.Cmp(tipCap) < 0 {
+		return fmt.Errorf("%w: tip %v, fee cap %v", ErrTipAboveFeeCap, tipCap, feeCap)
+	}
+
+	gas, err := IntrinsicGas(tx.inner.data(), tx.AccessList(), tx.To() == nil, rules)
+	if err != nil {
+		return err
+	}
+	if tx.Gas() < gas {
+		return fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, tx.Gas(), gas)
+	}
+
+	if opts.BaseFee != nil && feeCap.Cmp(opts.BaseFee) < 0 {
+		return fmt.Errorf("%w: fee cap %v, base fee %v", ErrFeeCapTooLow, feeCap, opts.BaseFee)
+	}
+	if opts.MinTip != nil {
+		if tip := tx.EffectiveGasTip(opts.BaseFee); tip.Cmp(opts.MinTip) < 0 {
+			return fmt.Errorf("%w: tip %v, minimum %v", ErrUnderpriced, tip, opts.MinTip)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/arcology-network/3rd-party/eth/common"
+	"github.com/arcology-network/3rd-party/eth/params"
+)
+
+func TestIntrinsicGas(t *testing.T) {
+	frontier := params.Rules{}
+	homestead := params.Rules{IsHomestead: true}
+	istanbul := params.Rules{IsHomestead: true, IsIstanbul: true}
+	accesses := AccessList{{Address: common.Address{1}, StorageKeys: []common.Hash{{1}, {2}}}}
+	tests := []struct {
+		data     []byte
+		list     AccessList
+		creation bool
+		rules    params.Rules
+		want     uint64
+	}{
+		{nil, nil, false, frontier, 21000},
+		{nil, nil, true, frontier, 21000},
+		{nil, nil, true, homestead, 53000},
+		{[]byte{0, 1, 1}, nil, false, homestead, 21000 + 4 + 2*68},
+		{[]byte{0, 1, 1}, nil, false, istanbul, 21000 + 4 + 2*16},
+		{[]byte{0, 1, 1}, nil, true, istanbul, 53000 + 4 + 2*16},
+		{nil, accesses, false, istanbul, 21000 + 2400 + 2*1900},
+	}
+	for i, test := range tests {
+		gas, err := IntrinsicGas(test.data, test.list, test.creation, test.rules)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if gas != test.want {
+			t.Errorf("test %d: got %d, want %d", i, gas, test.want)
+		}
+	}
+}
+
+func TestValidateTransaction(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID:        big.NewInt(1),
+		HomesteadBlock: big.NewInt(0),
+		IstanbulBlock:  big.NewInt(0),
+		BerlinBlock:    big.NewInt(0),
+		LondonBlock:    big.NewInt(10),
+	}
+	berlin, london := config.Rules(big.NewInt(1)), config.Rules(big.NewInt(10))
+
+	legacy := func(gas uint64, price int64, data []byte) *Transaction {
+		return NewTx(&LegacyTx{To: &testAddr, Gas: gas, GasPrice: big.NewInt(price), Value: big.NewInt(0), Data: data})
+	}
+	dynamic := func(gas uint64, tip, feeCap int64) *Transaction {
+		return NewTx(&DynamicFeeTx{ChainID: big.NewInt(1), To: &testAddr, Gas: gas, GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(feeCap), Value: big.NewInt(0)})
+	}
+	creation := NewTx(&AccessListTx{ChainID: big.NewInt(1), Gas: 53000, GasPrice: big.NewInt(1), Value: big.NewInt(0)})
+
+	tests := []struct {
+		tx    *Transaction
+		rules params.Rules
+		opts  TxValidationOptions
+		want  error
+	}{
+		{legacy(21000, 1, nil), berlin, TxValidationOptions{}, nil},
+		{legacy(21015, 1, []byte{1}), berlin, TxValidationOptions{}, ErrIntrinsicGas},
+		{legacy(21016, 1, []byte{1}), berlin, TxValidationOptions{}, nil},
+		{creation, berlin, TxValidationOptions{}, nil},
+		{creation, params.Rules{IsHomestead: true}, TxValidationOptions{}, ErrTxTypeNotSupported},
+		{dynamic(21000, 1, 2), berlin, TxValidationOptions{}, ErrTxTypeNotSupported},
+		{dynamic(21000, 1, 2), london, TxValidationOptions{}, nil},
+		{dynamic(21000, 3, 2), london, TxValidationOptions{}, ErrTipAboveFeeCap},
+		{dynamic(21000, 1, 2), london, TxValidationOptions{BaseFee: big.NewInt(3)}, ErrFeeCapTooLow},
+		{dynamic(21000, 2, 10), london, TxValidationOptions{BaseFee: big.NewInt(9), MinTip: big.NewInt(2)}, ErrUnderpriced},
+		{dynamic(21000, 2, 10), london, TxValidationOptions{BaseFee: big.NewInt(8), MinTip: big.NewInt(2)}, nil},
+		{legacy(21000, 1, nil), london, TxValidationOptions{MinTip: big.NewInt(2)}, ErrUnderpriced},
+		{legacy(30000, 1, nil), london, TxValidationOptions{MaxGas: 29999}, ErrGasLimit},
+		{NewTx(&DynamicFeeTx{ChainID: big.NewInt(1), Gas: 53000, GasTipCap: big.NewInt(1), GasFeeCap: new(big.Int).Lsh(big.NewInt(1), 256), Value: big.NewInt(0)}), london, TxValidationOptions{}, ErrFeeCapVeryHigh},
+	}
+	for i, test := range tests {
+		if err := ValidateTransaction(test.tx, test.rules, test.opts); !errors.Is(err, test.want) {
+			t.Errorf("test %d: got error %v, want %v", i, err, test.want)
+		}
+	}
+}
+
+func TestEffectiveGasTip(t *testing.T) {
+	tx := NewTx(&DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(10), Value: big.NewInt(0)})
+	for _, test := range []struct {
+		baseFee *big.Int
+		want    int64
+	}{
+		{nil, 2},
+		{big.NewInt(5), 2},
+		{big.NewInt(9), 1},
+		{big.NewInt(12), -2},
+	} {
+		if got := tx.EffectiveGasTip(test.baseFee); got.Int64() != test.want {
+			t.Errorf("base fee %v: got %v, want %d", test.baseFee, got, test.want)
+		}
+	}
+}